@@ -2,6 +2,7 @@ package auth
 
 import (
 	"database/sql"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -11,8 +12,14 @@ import (
 
 func EmailCheck(userRepository repository.UserRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		email := chi.URLParam(r, "email")
-		if _, err := userRepository.SelectIDByEmail(r.Context(), email); err != nil {
+		if _, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, email); err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("User not found"))
 				return
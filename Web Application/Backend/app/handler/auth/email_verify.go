@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"database/sql"
+	"errors"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"interview/summarization/token"
@@ -13,36 +15,25 @@ type VerifyEmailResponse struct {
 
 func VerifyEmail(userRepository repository.UserRepository, jwt token.JWT) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.FormValue("token")
+		tokenString := r.FormValue("token")
 		userID := r.FormValue("userID")
-		res, err := jwt.GetClaims(token)
+		res, err := jwt.GetClaims(tokenString)
 		if err != nil {
 			response.RespondError(w, response.BadRequestError("Invalid Token"))
 			return
 		}
 
-		if res.UserID != userID {
+		if res.Type != token.VerificationToken || res.UserID != userID {
 			response.RespondError(w, response.BadRequestError("Invalid Token"))
 			return
 		}
 
-		status, ok := repository.UserStatusMapper("VERIFIED")
-		if !ok {
-			response.RespondError(w, response.InternalServerError())
-			return
-		}
-
-		updatedUser := &repository.User{
-			ID:     userID,
-			Status: status,
-		}
+		if err := userRepository.Verify(r.Context(), userID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.ConflictError("Account is not awaiting verification"))
+				return
+			}
 
-		if err := userRepository.UpdateStatus(r.Context(), updatedUser); err != nil {
-			response.RespondError(w, response.InternalServerError())
-			return
-		}
-
-		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -18,11 +19,17 @@ type AllEmailResponse struct {
 
 func GetAllEmails(userRepository repository.UserRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		resp := AllEmailResponse{
 			Data: []EmailResponse{},
 		}
 
-		users, err := userRepository.SelectAll(r.Context())
+		users, err := userRepository.SelectAll(r.Context(), userCred.OrgID)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
@@ -1,15 +1,28 @@
 package auth
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"html/template"
 	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/lockout"
 	"interview/summarization/repository"
 	"interview/summarization/token"
+	"math"
+	"net"
 	"net/http"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,13 +39,39 @@ type (
 	}
 
 	LoginResponse struct {
-		AccessToken  Token  `json:"access_token"`
-		RefreshToken Token  `json:"refresh_token"`
-		Role         string `json:"role"`
+		AccessToken   Token    `json:"access_token"`
+		RefreshToken  Token    `json:"refresh_token"`
+		Role          string   `json:"role"`
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	}
+
+	// ChallengeResponse asks for the second factor of a login. When
+	// SetupRequired is true the user still has to set up an authenticator,
+	// they were emailed a link to do so and get no challenge token.
+	ChallengeResponse struct {
+		TwoFactorRequired bool   `json:"two_factor_required"`
+		SetupRequired     bool   `json:"setup_required"`
+		ChallengeToken    *Token `json:"challenge_token,omitempty"`
 	}
 )
 
-func Login(userRepository repository.UserRepository, jwt token.JWT) http.HandlerFunc {
+// unknownUserPassword is checked against when the email isn't registered so
+// the answer doesn't come back any sooner than for a wrong password.
+var unknownUserPassword, _ = bcrypt.GenerateFromPassword([]byte("unknown user"), bcrypt.DefaultCost)
+
+// Login signs a user in. Every failure counts against the email and the IP
+// address it came from, and the answer never tells whether the email is
+// registered. Users with two-factor authentication get a challenge token to
+// finish with LoginTwoFactor instead, users whose role requires it but who
+// haven't set it up are emailed a link to LoginTwoFactorSetup.
+func Login(
+	userRepository repository.UserRepository,
+	sessionRepository repository.SessionRepository,
+	twoFactorRepository repository.TwoFactorRepository,
+	guard *lockout.Guard,
+	jwt token.JWT,
+	cfg config.Config,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		req := LoginRequest{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -40,10 +79,32 @@ func Login(userRepository repository.UserRepository, jwt token.JWT) http.Handler
 			return
 		}
 
+		ip := clientIP(r)
+		lockedUntil, err := guard.LockedUntil(r.Context(), req.Email, ip)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if lockedUntil.Valid {
+			respondLocked(w, lockedUntil.Time)
+			return
+		}
+
+		fail := func(isRegistered bool) {
+			if err := recordFailure(r.Context(), guard, cfg, req.Email, ip, isRegistered); err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			response.RespondError(w, response.UnauthorizedError("Invalid credentials"))
+		}
+
 		user, err := userRepository.SelectIDPasswordRoleByEmail(r.Context(), req.Email)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				response.RespondError(w, response.UnauthorizedError("Invalid credentials"))
+				// takes as long as a wrong password would
+				bcrypt.CompareHashAndPassword(unknownUserPassword, []byte(req.Password))
+				fail(false)
 				return
 			}
 
@@ -52,7 +113,7 @@ func Login(userRepository repository.UserRepository, jwt token.JWT) http.Handler
 		}
 
 		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-			response.RespondError(w, response.UnauthorizedError("Invalid credentials"))
+			fail(true)
 			return
 		}
 
@@ -62,33 +123,189 @@ func Login(userRepository repository.UserRepository, jwt token.JWT) http.Handler
 			return
 		}
 		if status != repository.Veryfied {
-			response.RespondError(w, response.UnauthorizedError("User is not verified"))
+			response.RespondError(w, response.UnauthorizedError("Invalid credentials"))
 			return
 		}
 
-		atClaim := token.JWTClaim{
-			UserID: user.ID,
-			Role:   string(user.Role),
+		// the failures are only forgotten once the second factor is right too
+		isEnabled, isRequired, err := twoFactorState(r.Context(), twoFactorRepository, user)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !isEnabled && isRequired {
+			go sendTwoFactorEnrollmentEmail(cfg, jwt, user.ID, req.Email)
+
+			response.Respond(w, http.StatusOK, ChallengeResponse{
+				TwoFactorRequired: true,
+				SetupRequired:     true,
+			})
+			return
+		}
+		if isEnabled {
+			challenge, err := jwt.CreateChallengeToken(token.JWTClaim{
+				UserID: user.ID,
+			})
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			response.Respond(w, http.StatusOK, ChallengeResponse{
+				TwoFactorRequired: true,
+				ChallengeToken: &Token{
+					Token:     challenge.Token,
+					Scheme:    challenge.Scheme,
+					ExpiresAt: challenge.ExpiresAt.Format(time.RFC3339),
+				},
+			})
+			return
 		}
-		accessToken, err := jwt.CreateAccessToken(atClaim)
 
-		rtClaim := token.JWTClaim{
-			UserID: user.ID,
+		if err := guard.Unlock(r.Context(), req.Email); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
 		}
-		refreshToken, err := jwt.CreateRefreshToken(rtClaim)
-
-		response.Respond(w, http.StatusOK, LoginResponse{
-			AccessToken: Token{
-				Token:     accessToken.Token,
-				Scheme:    accessToken.Scheme,
-				ExpiresAt: accessToken.ExpiresAt.Format(time.RFC3339),
-			},
-			RefreshToken: Token{
-				Token:     refreshToken.Token,
-				Scheme:    refreshToken.Scheme,
-				ExpiresAt: refreshToken.ExpiresAt.Format(time.RFC3339),
-			},
-			Role: string(user.Role),
-		})
+
+		resp, err := startSession(r, sessionRepository, jwt, user)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, resp)
 	}
 }
+
+// startSession stores a new session of the user and signs its tokens.
+func startSession(r *http.Request, sessionRepository repository.SessionRepository, jwt token.JWT, user *repository.User) (LoginResponse, error) {
+	sessionId := uuid.NewString()
+	resp, refreshToken, err := issueTokens(jwt, user.ID, string(user.Role), sessionId)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	err = sessionRepository.Insert(r.Context(), &repository.Session{
+		ID:        sessionId,
+		UserID:    user.ID,
+		UserAgent: r.UserAgent(),
+		IPAddress: r.RemoteAddr,
+		ExpiresAt: refreshToken.ExpiresAt,
+	}, &repository.RefreshToken{
+		ID:        refreshToken.Claim.ID,
+		UserID:    user.ID,
+		ExpiresAt: refreshToken.ExpiresAt,
+	})
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// clientIP drops the port the request came from, the IP address is what a
+// lockout is kept for.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// recordFailure counts a failed login and lets a registered user know when
+// it locked their account.
+func recordFailure(ctx context.Context, guard *lockout.Guard, cfg config.Config, email, ip string, isRegistered bool) error {
+	lockedUntil, err := guard.Fail(ctx, email, ip)
+	if err != nil {
+		return err
+	}
+	if lockedUntil.Valid && isRegistered {
+		go sendAccountLockedEmail(cfg, email, lockedUntil.Time)
+	}
+
+	return nil
+}
+
+func respondLocked(w http.ResponseWriter, lockedUntil time.Time) {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	response.RespondError(w, response.TooManyRequestsError("Too many failed login attempts, try again later"))
+}
+
+func sendAccountLockedEmail(cfg config.Config, receiver string, lockedUntil time.Time) {
+	auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Println("Error getting current working directory:", err)
+		return
+	}
+	tmplPath := filepath.Join(cwd, "/email_templates/account_locked_template.html")
+
+	tmpl, err := template.ParseFiles(tmplPath)
+	if err != nil {
+		fmt.Println("Error parsing template:", err)
+		return
+	}
+
+	data := struct {
+		LockedUntil string
+	}{
+		LockedUntil: lockedUntil.Format("02 Jan 2006 15:04 MST"),
+	}
+
+	var renderedContent bytes.Buffer
+	err = tmpl.Execute(&renderedContent, data)
+	if err != nil {
+		fmt.Println("Error executing template:", err)
+		return
+	}
+
+	content := "Subject: Akun Hiremif Dikunci Sementara\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
+	err = smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
+	if err != nil {
+		fmt.Println("Error sending email:", err)
+	}
+}
+
+// issueTokens signs a pair of access and refresh tokens for the session, the
+// refresh token is returned as well so the caller can store it.
+func issueTokens(jwt token.JWT, userId, role, sessionId string) (LoginResponse, *token.JWTToken, error) {
+	accessToken, err := jwt.CreateAccessToken(token.JWTClaim{
+		UserID:    userId,
+		Role:      role,
+		SessionID: sessionId,
+	})
+	if err != nil {
+		return LoginResponse{}, nil, err
+	}
+
+	refreshToken, err := jwt.CreateRefreshToken(token.JWTClaim{
+		UserID:    userId,
+		Role:      role,
+		SessionID: sessionId,
+	})
+	if err != nil {
+		return LoginResponse{}, nil, err
+	}
+
+	return LoginResponse{
+		AccessToken: Token{
+			Token:     accessToken.Token,
+			Scheme:    accessToken.Scheme,
+			ExpiresAt: accessToken.ExpiresAt.Format(time.RFC3339),
+		},
+		RefreshToken: Token{
+			Token:     refreshToken.Token,
+			Scheme:    refreshToken.Scheme,
+			ExpiresAt: refreshToken.ExpiresAt.Format(time.RFC3339),
+		},
+		Role: role,
+	}, refreshToken, nil
+}
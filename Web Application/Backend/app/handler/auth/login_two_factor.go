@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/lockout"
+	"interview/summarization/repository"
+	"interview/summarization/token"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// LoginTwoFactorRequest carries a challenge token to sign in with an
+	// authenticator that is set up, or an enrollment token to turn on the
+	// one shared by LoginTwoFactorSetup.
+	LoginTwoFactorRequest struct {
+		ChallengeToken  string `json:"challenge_token"`
+		EnrollmentToken string `json:"enrollment_token"`
+		Code            string `json:"code"`
+		RecoveryCode    string `json:"recovery_code"`
+	}
+
+	LoginTwoFactorSetupRequest struct {
+		EnrollmentToken string `json:"enrollment_token"`
+	}
+)
+
+// challengeUser returns the user a challenge or enrollment token was issued
+// to, as long as they can still sign in.
+func challengeUser(r *http.Request, userRepository repository.UserRepository, jwt token.JWT, tokenString string, tokenType token.TokenType) (*repository.User, error) {
+	claim, err := jwt.GetClaims(tokenString)
+	if err != nil || claim.Type != tokenType {
+		return nil, sql.ErrNoRows
+	}
+
+	user, err := userRepository.SelectOrgRoleStatusByID(r.Context(), claim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != repository.Veryfied {
+		return nil, sql.ErrNoRows
+	}
+
+	return user, nil
+}
+
+// LoginTwoFactor finishes a login with a code of the authenticator or a
+// recovery code. A user whose role requires two-factor authentication
+// enables it here with their enrollment token and the first code of the
+// secret from LoginTwoFactorSetup, and gets their recovery codes along with
+// the tokens.
+func LoginTwoFactor(
+	userRepository repository.UserRepository,
+	sessionRepository repository.SessionRepository,
+	twoFactorRepository repository.TwoFactorRepository,
+	guard *lockout.Guard,
+	jwt token.JWT,
+	cfg config.Config,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := LoginTwoFactorRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		tokenString, tokenType := req.ChallengeToken, token.ChallengeToken
+		if req.EnrollmentToken != "" {
+			tokenString, tokenType = req.EnrollmentToken, token.EnrollmentToken
+		}
+
+		user, err := challengeUser(r, userRepository, jwt, tokenString, tokenType)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.UnauthorizedError("Invalid token"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		profile, err := userRepository.SelectNamePhoneEmailByID(r.Context(), user.ID)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		// codes are guessed as easily as passwords and count the same way
+		ip := clientIP(r)
+		lockedUntil, err := guard.LockedUntil(r.Context(), profile.Email, ip)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if lockedUntil.Valid {
+			respondLocked(w, lockedUntil.Time)
+			return
+		}
+
+		twoFactor, err := twoFactorRepository.SelectByUserID(r.Context(), user.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Two-factor authentication is not set up"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		// only the emailed enrollment token turns an authenticator on, the
+		// password alone doesn't
+		if twoFactor.EnabledAt.Valid && tokenType != token.ChallengeToken {
+			response.RespondError(w, response.ConflictError("Two-factor authentication is already enabled"))
+			return
+		}
+		if !twoFactor.EnabledAt.Valid && tokenType != token.EnrollmentToken {
+			response.RespondError(w, response.BadRequestError("Two-factor authentication is not set up"))
+			return
+		}
+
+		var ok bool
+		var recoveryCodes []string
+		if twoFactor.EnabledAt.Valid {
+			ok, err = verifySecondFactor(r.Context(), twoFactorRepository, twoFactor, req.Code, req.RecoveryCode)
+		} else {
+			recoveryCodes, err = enableTwoFactor(r.Context(), twoFactorRepository, twoFactor, req.Code)
+			ok = err == nil
+			if errors.Is(err, sql.ErrNoRows) {
+				err = nil
+			}
+		}
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !ok {
+			if err := recordFailure(r.Context(), guard, cfg, profile.Email, ip, true); err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			response.RespondError(w, response.UnauthorizedError("Invalid code"))
+			return
+		}
+
+		if err := guard.Unlock(r.Context(), profile.Email); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp, err := startSession(r, sessionRepository, jwt, user)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		resp.RecoveryCodes = recoveryCodes
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
+
+// LoginTwoFactorSetup shares a secret with a user who has to set up
+// two-factor authentication before they can sign in. It takes the
+// enrollment token emailed on their login rather than the password.
+func LoginTwoFactorSetup(
+	userRepository repository.UserRepository,
+	twoFactorRepository repository.TwoFactorRepository,
+	jwt token.JWT,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := LoginTwoFactorSetupRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		user, err := challengeUser(r, userRepository, jwt, req.EnrollmentToken, token.EnrollmentToken)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.UnauthorizedError("Invalid token"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp, err := setupTwoFactor(r.Context(), userRepository, twoFactorRepository, user.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrTwoFactorEnabled) {
+				response.RespondError(w, response.ConflictError("Two-factor authentication is already enabled"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func sendTwoFactorEnrollmentEmail(cfg config.Config, jwt token.JWT, userID, receiver string) {
+	auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
+
+	enrollment, err := jwt.CreateEnrollmentToken(token.JWTClaim{
+		UserID: userID,
+	})
+	if err != nil {
+		fmt.Println("Error creating enrollment token:", err)
+		return
+	}
+
+	urlsetup := fmt.Sprintf("http://%s:%s/auth/two-factor/setup?token=%s", cfg.FEHost, cfg.FEPort, url.QueryEscape(enrollment.Token))
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Println("Error getting current working directory:", err)
+		return
+	}
+	tmplPath := filepath.Join(cwd, "/email_templates/two_factor_enrollment_template.html")
+
+	tmpl, err := template.ParseFiles(tmplPath)
+	if err != nil {
+		fmt.Println("Error parsing template:", err)
+		return
+	}
+
+	data := struct {
+		SetupURL template.HTML
+	}{
+		SetupURL: template.HTML(urlsetup),
+	}
+
+	var renderedContent bytes.Buffer
+	err = tmpl.Execute(&renderedContent, data)
+	if err != nil {
+		fmt.Println("Error executing template:", err)
+		return
+	}
+
+	content := "Subject: Two-Factor Authentication Setup Hiremif\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
+	err = smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
+	if err != nil {
+		fmt.Println("Error sending email:", err)
+	}
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"interview/summarization/session"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultPasswordResetExpire = 60 // minutes
+
+type (
+	ForgotPasswordRequest struct {
+		Email string `json:"email"`
+	}
+
+	ResetPasswordRequest struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+)
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewResetToken returns a random token to mail to the user and the hash
+// to store for it.
+func NewResetToken() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	return token, hashResetToken(token), nil
+}
+
+// ForgotPassword mails a single-use reset link. It answers the same whether
+// or not the email is registered, so it can't be used to look up accounts.
+func ForgotPassword(
+	userRepository repository.UserRepository,
+	passwordResetRepository repository.PasswordResetRepository,
+	cfg config.Config,
+) http.HandlerFunc {
+	expire := cfg.PasswordResetExpire
+	if expire <= 0 {
+		expire = defaultPasswordResetExpire
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ForgotPasswordRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		if req.Email == "" {
+			response.RespondError(w, response.BadRequestError("Email is required"))
+			return
+		}
+
+		user, err := userRepository.SelectIDByEmail(r.Context(), req.Email)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondOK(w)
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		token, tokenHash, err := NewResetToken()
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		err = passwordResetRepository.Insert(r.Context(), &repository.PasswordReset{
+			ID:        uuid.NewString(),
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			ExpiresAt: time.Now().UTC().Add(time.Duration(expire) * time.Minute),
+		})
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		go func(name, receiver, token string) {
+			auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
+
+			urlreset := fmt.Sprintf("http://%s:%s/auth/reset-password?token=%s", cfg.FEHost, cfg.FEPort, url.QueryEscape(token))
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				fmt.Println("Error getting current working directory:", err)
+				return
+			}
+			tmplPath := filepath.Join(cwd, "/email_templates/reset_password_template.html")
+
+			tmpl, err := template.ParseFiles(tmplPath)
+			if err != nil {
+				fmt.Println("Error parsing template:", err)
+				return
+			}
+
+			data := struct {
+				Name      string
+				ResetURL  template.HTML
+				ExpiresIn int
+			}{
+				Name:      name,
+				ResetURL:  template.HTML(urlreset),
+				ExpiresIn: expire,
+			}
+
+			var renderedContent bytes.Buffer
+			err = tmpl.Execute(&renderedContent, data)
+			if err != nil {
+				fmt.Println("Error executing template:", err)
+				return
+			}
+
+			content := "Subject: Reset Password Hiremif\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
+			err = smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
+			if err != nil {
+				fmt.Println("Error sending email:", err)
+			}
+		}(user.Name, user.Email, token)
+
+		response.RespondOK(w)
+	}
+}
+
+// ResetPassword sets a new password with the token from the reset link and
+// logs the user out of every device.
+func ResetPassword(
+	passwordResetRepository repository.PasswordResetRepository,
+	sessionRepository repository.SessionRepository,
+	revocations *session.RevocationList,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ResetPasswordRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		if req.Token == "" || req.NewPassword == "" {
+			response.RespondError(w, response.BadRequestError("Token and new password are required"))
+			return
+		}
+
+		hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		userId, err := passwordResetRepository.Consume(r.Context(), hashResetToken(req.Token), string(hashedPass))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Invalid or expired token"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		revoked, err := sessionRepository.RevokeAllByUserID(r.Context(), userId, "")
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		revocations.Add(revoked...)
+
+		response.RespondOK(w)
+	}
+}
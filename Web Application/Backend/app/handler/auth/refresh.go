@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"interview/summarization/session"
+	"interview/summarization/token"
+	"net/http"
+)
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh trades a refresh token for a new pair of tokens. Every refresh
+// token can only be used once, showing up with one that was already used
+// means it was stolen, so the whole session is revoked.
+func Refresh(
+	refreshTokenRepository repository.RefreshTokenRepository,
+	sessionRepository repository.SessionRepository,
+	revocations *session.RevocationList,
+	jwt token.JWT,
+) http.HandlerFunc {
+	revokeSession := func(ctx context.Context, sessionId string) {
+		if err := sessionRepository.Revoke(ctx, sessionId); err != nil {
+			fmt.Println(err)
+			return
+		}
+		revocations.Add(sessionId)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := RefreshRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		claim, err := jwt.GetClaims(req.RefreshToken)
+		if err != nil || claim.Type != token.RefreshToken || claim.ID == "" {
+			response.RespondError(w, response.UnauthorizedError("Invalid refresh token"))
+			return
+		}
+
+		stored, err := refreshTokenRepository.SelectOneByID(r.Context(), claim.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.UnauthorizedError("Invalid refresh token"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if stored.UserID != claim.UserID {
+			response.RespondError(w, response.UnauthorizedError("Invalid refresh token"))
+			return
+		}
+
+		if stored.RevokedAt.Valid {
+			// a token that was rotated out is being replayed
+			if stored.ReplacedBy.Valid {
+				revokeSession(r.Context(), stored.SessionID)
+			}
+
+			response.RespondError(w, response.UnauthorizedError("Invalid refresh token"))
+			return
+		}
+
+		resp, refreshToken, err := issueTokens(jwt, claim.UserID, claim.Role, stored.SessionID)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		err = refreshTokenRepository.Rotate(r.Context(), stored.ID, &repository.RefreshToken{
+			ID:        refreshToken.Claim.ID,
+			UserID:    stored.UserID,
+			SessionID: stored.SessionID,
+			ExpiresAt: refreshToken.ExpiresAt,
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrTokenReused) {
+				revokeSession(r.Context(), stored.SessionID)
+
+				response.RespondError(w, response.UnauthorizedError("Invalid refresh token"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
@@ -1,22 +1,18 @@
 package auth
 
 import (
-	"context"
+	"database/sql"
 	"encoding/json"
-	"fmt"
-	"bytes"
+	"errors"
 	"interview/summarization/app/response"
 	"interview/summarization/config"
 	"interview/summarization/repository"
 	"interview/summarization/token"
 	"net/http"
-	"net/smtp"
 	"strings"
-	"html/template"
+	"time"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
-	"os"
-	"path/filepath"
 )
 
 type RegisterRequest struct {
@@ -25,9 +21,17 @@ type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Role     string `json:"role"`
+	// OrgID is the organization the candidate applies to, registration
+	// links carry it. Sign-ups without one join the default organization.
+	OrgID    string `json:"org_id"`
 }
 
-func Register(userRepository repository.UserRepository, jwt token.JWT, cfg config.Config) http.HandlerFunc {
+func Register(
+	userRepository repository.UserRepository,
+	organizationRepository repository.OrganizationRepository,
+	jwt token.JWT,
+	cfg config.Config,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		req := RegisterRequest{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -35,12 +39,33 @@ func Register(userRepository repository.UserRepository, jwt token.JWT, cfg confi
 			return
 		}
 
+		// interviewers and HRD are invited by HRD
+		if req.Role == "" {
+			req.Role = string(repository.Interviewee)
+		}
 		role, ok := repository.UserRoleMapper(req.Role)
-		if !ok {
+		if !ok || role != repository.Interviewee {
 			response.RespondError(w, response.BadRequestError("Invalid Role"))
 			return
 		}
 
+		if req.OrgID == "" {
+			req.OrgID = cfg.DefaultOrganization()
+		}
+		if _, err := uuid.Parse(req.OrgID); err != nil {
+			response.RespondError(w, response.BadRequestError("Invalid Organization"))
+			return
+		}
+		if _, err := organizationRepository.SelectOneByID(r.Context(), req.OrgID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Invalid Organization"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
@@ -60,53 +85,16 @@ func Register(userRepository repository.UserRepository, jwt token.JWT, cfg confi
 
 		newUser := &repository.User{
 			ID:       uuid.NewString(),
+			OrgID:    req.OrgID,
 			Name:     req.Name,
 			Phone:    req.Phone,
 			Email:    req.Email,
 			Password: string(hashedPass),
 			Role:     role,
 			Status:   status,
+			VerificationSentAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
 		}
 
-		go func(ctx context.Context, userID, receiver string, jwt token.JWT) {
-			auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
-
-			token, _ := jwt.CreateAccessToken(token.JWTClaim{
-				UserID: userID,
-			})
-
-			urlverify := fmt.Sprintf("http://%s:%s/auth/verify-email?token=%s&userID=%s", cfg.FEHost, cfg.FEPort, token.Token, userID)
-
-			cwd, err := os.Getwd()
-			if err != nil {
-					fmt.Println("Error getting current working directory:", err)
-					return
-			}
-			tmplPath := filepath.Join(cwd, "/email_templates/register_template.html")
-
-			tmpl, err := template.ParseFiles(tmplPath)
-			if err != nil {
-					fmt.Println("Error parsing template:", err)
-					return
-			}
-
-			data := struct {
-        VerifyURL template.HTML
-	    }{
-        VerifyURL: template.HTML(urlverify),
-		  }
-
-			var renderedContent bytes.Buffer
-			err = tmpl.Execute(&renderedContent, data)
-			if err != nil {
-					fmt.Println("Error executing template:", err)
-					return
-			}
-
-			content := "Subject: Email Verification Hiremif\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
-			smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
-		}(context.Background(), newUser.ID, req.Email, jwt)
-
 		if err := userRepository.Insert(r.Context(), newUser); err != nil {
 			if strings.Contains(err.Error(), "unique constraint") {
 				response.RespondError(w, response.UnauthorizedError("Invalid credentials"))
@@ -117,6 +105,8 @@ func Register(userRepository repository.UserRepository, jwt token.JWT, cfg confi
 			return
 		}
 
+		go sendVerificationEmail(cfg, jwt, newUser.ID, newUser.Email)
+
 		response.RespondOK(w)
 	}
 }
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"interview/summarization/session"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type (
+	LogoutRequest struct {
+		All bool `json:"all"`
+	}
+
+	Session struct {
+		ID         string `json:"id"`
+		UserAgent  string `json:"user_agent"`
+		IPAddress  string `json:"ip_address"`
+		CreatedAt  string `json:"created_at"`
+		LastSeenAt string `json:"last_seen_at"`
+		ExpiresAt  string `json:"expires_at"`
+		Current    bool   `json:"current"`
+	}
+
+	GetSessionsResponse struct {
+		Data []Session `json:"data"`
+	}
+)
+
+// Logout ends the session of the token, or every session of the user when
+// asked to log out of all devices.
+func Logout(sessionRepository repository.SessionRepository, revocations *session.RevocationList) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := LogoutRequest{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+				return
+			}
+		}
+
+		if req.All {
+			revoked, err := sessionRepository.RevokeAllByUserID(r.Context(), userCred.ID, "")
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+			revocations.Add(revoked...)
+			revocations.Add(userCred.SessionID)
+
+			response.RespondOK(w)
+			return
+		}
+
+		if err := sessionRepository.Revoke(r.Context(), userCred.SessionID); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		revocations.Add(userCred.SessionID)
+
+		response.RespondOK(w)
+	}
+}
+
+func GetSessions(sessionRepository repository.SessionRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		sessions, err := sessionRepository.SelectActiveByUserID(r.Context(), userCred.ID)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetSessionsResponse{
+			Data: []Session{},
+		}
+		for _, s := range sessions {
+			resp.Data = append(resp.Data, Session{
+				ID:         s.ID,
+				UserAgent:  s.UserAgent,
+				IPAddress:  s.IPAddress,
+				CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+				LastSeenAt: s.LastSeenAt.Format(time.RFC3339),
+				ExpiresAt:  s.ExpiresAt.Format(time.RFC3339),
+				Current:    s.ID == userCred.SessionID,
+			})
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func DeleteSession(sessionRepository repository.SessionRepository, revocations *session.RevocationList) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		sessionId := chi.URLParam(r, "id")
+
+		s, err := sessionRepository.SelectOneByID(r.Context(), sessionId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Session not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if s.UserID != userCred.ID {
+			response.RespondError(w, response.NotFoundError("Session not found"))
+			return
+		}
+
+		if err := sessionRepository.Revoke(r.Context(), sessionId); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		revocations.Add(sessionId)
+
+		response.RespondOK(w)
+	}
+}
@@ -0,0 +1,436 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"interview/summarization/totp"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorIssuer   = "HireMIF"
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10 // bytes
+)
+
+type (
+	TwoFactorStatusResponse struct {
+		Enabled           bool `json:"enabled"`
+		Required          bool `json:"required"`
+		RecoveryCodesLeft int  `json:"recovery_codes_left"`
+	}
+
+	// TwoFactorSetupResponse is shown to the user once, the provisioning URI
+	// is meant to be rendered as a QR code.
+	TwoFactorSetupResponse struct {
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioning_uri"`
+	}
+
+	TwoFactorCodeRequest struct {
+		Code string `json:"code"`
+	}
+
+	RecoveryCodesResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	DisableTwoFactorRequest struct {
+		Password     string `json:"password"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// normalizeRecoveryCode lets a recovery code be typed without its dashes or
+// in any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRecoveryCodes returns the recovery codes to show to the user and the
+// hashes to store for them.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		secret := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(secret))
+		codes[i] = code[:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:]
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	return codes, hashes, nil
+}
+
+// twoFactorState tells whether the user has two-factor authentication on and
+// whether the organization requires it for their role.
+func twoFactorState(ctx context.Context, twoFactorRepository repository.TwoFactorRepository, user *repository.User) (bool, bool, error) {
+	isEnabled := false
+	twoFactor, err := twoFactorRepository.SelectByUserID(ctx, user.ID)
+	if err == nil {
+		isEnabled = twoFactor.EnabledAt.Valid
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return false, false, err
+	}
+
+	isRequired, err := twoFactorRepository.IsRequired(ctx, user.OrgID, user.Role)
+	if err != nil {
+		return false, false, err
+	}
+
+	return isEnabled, isRequired, nil
+}
+
+// setupTwoFactor shares a new secret with the authenticator of the user, it
+// only counts once enableTwoFactor has seen a code of it.
+func setupTwoFactor(
+	ctx context.Context,
+	userRepository repository.UserRepository,
+	twoFactorRepository repository.TwoFactorRepository,
+	userId string,
+) (TwoFactorSetupResponse, error) {
+	user, err := userRepository.SelectNamePhoneEmailByID(ctx, userId)
+	if err != nil {
+		return TwoFactorSetupResponse{}, err
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return TwoFactorSetupResponse{}, err
+	}
+
+	if err := twoFactorRepository.UpsertPending(ctx, userId, secret); err != nil {
+		return TwoFactorSetupResponse{}, err
+	}
+
+	return TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.URI(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// enableTwoFactor turns the pending secret on when the code is one of its
+// own and returns the recovery codes of the user. It returns sql.ErrNoRows
+// when the code is wrong or there is no pending secret.
+func enableTwoFactor(ctx context.Context, twoFactorRepository repository.TwoFactorRepository, twoFactor *repository.TwoFactor, code string) ([]string, error) {
+	if twoFactor.EnabledAt.Valid {
+		return nil, repository.ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := twoFactorRepository.Enable(ctx, twoFactor.UserID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verifySecondFactor checks a code of the authenticator or, when given, a
+// recovery code. Either can only be used once.
+func verifySecondFactor(ctx context.Context, twoFactorRepository repository.TwoFactorRepository, twoFactor *repository.TwoFactor, code, recoveryCode string) (bool, error) {
+	if !twoFactor.EnabledAt.Valid {
+		return false, nil
+	}
+
+	var err error
+	if recoveryCode != "" {
+		err = twoFactorRepository.UseRecoveryCode(ctx, twoFactor.UserID, hashRecoveryCode(recoveryCode))
+	} else {
+		step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		err = twoFactorRepository.UseStep(ctx, twoFactor.UserID, step)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func GetTwoFactor(twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		isEnabled, isRequired, err := twoFactorState(r.Context(), twoFactorRepository, &repository.User{
+			ID:    userCred.ID,
+			OrgID: userCred.OrgID,
+			Role:  userCred.Role,
+		})
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		recoveryCodesLeft := 0
+		if isEnabled {
+			recoveryCodesLeft, err = twoFactorRepository.CountRecoveryCodes(r.Context(), userCred.ID)
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+		}
+
+		response.Respond(w, http.StatusOK, TwoFactorStatusResponse{
+			Enabled:           isEnabled,
+			Required:          isRequired,
+			RecoveryCodesLeft: recoveryCodesLeft,
+		})
+	}
+}
+
+// SetupTwoFactor starts the enrolment of an authenticator, calling it again
+// before the enrolment is verified replaces the secret.
+func SetupTwoFactor(userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp, err := setupTwoFactor(r.Context(), userRepository, twoFactorRepository, userCred.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrTwoFactorEnabled) {
+				response.RespondError(w, response.ConflictError("Two-factor authentication is already enabled"))
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
+
+// VerifyTwoFactor finishes the enrolment with the first code of the
+// authenticator and hands out the recovery codes.
+func VerifyTwoFactor(twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := TwoFactorCodeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		twoFactor, err := twoFactorRepository.SelectByUserID(r.Context(), userCred.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Two-factor authentication is not set up"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		codes, err := enableTwoFactor(r.Context(), twoFactorRepository, twoFactor, req.Code)
+		if err != nil {
+			if errors.Is(err, repository.ErrTwoFactorEnabled) {
+				response.RespondError(w, response.ConflictError("Two-factor authentication is already enabled"))
+				return
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Invalid code"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, RecoveryCodesResponse{
+			RecoveryCodes: codes,
+		})
+	}
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user, used or
+// not, after checking a code of the authenticator.
+func RegenerateRecoveryCodes(twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := TwoFactorCodeRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		twoFactor, err := twoFactorRepository.SelectByUserID(r.Context(), userCred.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Two-factor authentication is not enabled"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !twoFactor.EnabledAt.Valid {
+			response.RespondError(w, response.BadRequestError("Two-factor authentication is not enabled"))
+			return
+		}
+
+		ok, err = verifySecondFactor(r.Context(), twoFactorRepository, twoFactor, req.Code, "")
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid code"))
+			return
+		}
+
+		codes, hashes, err := newRecoveryCodes()
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := twoFactorRepository.ReplaceRecoveryCodes(r.Context(), userCred.ID, hashes); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, RecoveryCodesResponse{
+			RecoveryCodes: codes,
+		})
+	}
+}
+
+// DisableTwoFactor turns two-factor authentication off with the password and
+// a second factor, unless the role of the user requires it.
+func DisableTwoFactor(userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := DisableTwoFactorRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		isRequired, err := twoFactorRepository.IsRequired(r.Context(), userCred.OrgID, userCred.Role)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if isRequired {
+			response.RespondError(w, response.ForbiddenError("Two-factor authentication is required for your role"))
+			return
+		}
+
+		user, err := userRepository.SelectPasswordByID(r.Context(), userCred.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Current Password"))
+			return
+		}
+
+		twoFactor, err := twoFactorRepository.SelectByUserID(r.Context(), userCred.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.BadRequestError("Two-factor authentication is not enabled"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		// a pending enrolment can go without proof, it never protected anything
+		if twoFactor.EnabledAt.Valid {
+			ok, err := verifySecondFactor(r.Context(), twoFactorRepository, twoFactor, req.Code, req.RecoveryCode)
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+			if !ok {
+				response.RespondError(w, response.BadRequestError("Invalid code"))
+				return
+			}
+		}
+
+		if err := twoFactorRepository.Delete(r.Context(), userCred.ID); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
+	"interview/summarization/session"
 	"net/http"
 
 	"golang.org/x/crypto/bcrypt"
@@ -16,7 +17,11 @@ type UpdatePasswordRequest struct {
 	NewPassword     string `json:"new_password"`
 }
 
-func UpdatePassword(userRepository repository.UserRepository) http.HandlerFunc {
+func UpdatePassword(
+	userRepository repository.UserRepository,
+	sessionRepository repository.SessionRepository,
+	revocations *session.RevocationList,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
 		if !ok {
@@ -66,6 +71,14 @@ func UpdatePassword(userRepository repository.UserRepository) http.HandlerFunc {
 			return
 		}
 
+		// the other devices logged in with the old password
+		revoked, err := sessionRepository.RevokeAllByUserID(r.Context(), userCred.ID, userCred.SessionID)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		revocations.Add(revoked...)
+
 		response.RespondOK(w)
 	}
 }
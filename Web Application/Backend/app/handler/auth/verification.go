@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"interview/summarization/token"
+	"net/http"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultVerificationResendCooldown = 60 // seconds
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+func sendVerificationEmail(cfg config.Config, jwt token.JWT, userID, receiver string) {
+	auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
+
+	token, err := jwt.CreateVerificationToken(token.JWTClaim{
+		UserID: userID,
+	})
+	if err != nil {
+		fmt.Println("Error creating verification token:", err)
+		return
+	}
+
+	urlverify := fmt.Sprintf("http://%s:%s/auth/verify-email?token=%s&userID=%s", cfg.FEHost, cfg.FEPort, token.Token, userID)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Println("Error getting current working directory:", err)
+		return
+	}
+	tmplPath := filepath.Join(cwd, "/email_templates/register_template.html")
+
+	tmpl, err := template.ParseFiles(tmplPath)
+	if err != nil {
+		fmt.Println("Error parsing template:", err)
+		return
+	}
+
+	data := struct {
+		VerifyURL template.HTML
+	}{
+		VerifyURL: template.HTML(urlverify),
+	}
+
+	var renderedContent bytes.Buffer
+	err = tmpl.Execute(&renderedContent, data)
+	if err != nil {
+		fmt.Println("Error executing template:", err)
+		return
+	}
+
+	content := "Subject: Email Verification Hiremif\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
+	err = smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
+	if err != nil {
+		fmt.Println("Error sending email:", err)
+	}
+}
+
+// ResendVerification mails a fresh verification link to an unverified user,
+// at most once per cooldown. Unknown and verified emails get the same answer.
+func ResendVerification(userRepository repository.UserRepository, jwt token.JWT, cfg config.Config) http.HandlerFunc {
+	cooldown := cfg.VerificationResendCooldown
+	if cooldown <= 0 {
+		cooldown = defaultVerificationResendCooldown
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := ResendVerificationRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		if req.Email == "" {
+			response.RespondError(w, response.BadRequestError("Email is required"))
+			return
+		}
+
+		user, err := userRepository.SelectIDPasswordRoleByEmail(r.Context(), req.Email)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondOK(w)
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if user.Status != repository.Unverified {
+			response.RespondOK(w)
+			return
+		}
+
+		ok, err := userRepository.MarkVerificationSent(r.Context(), user.ID, time.Now().UTC(), time.Duration(cooldown)*time.Second)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !ok {
+			response.RespondError(w, response.TooManyRequestsError("Please wait before requesting another verification email"))
+			return
+		}
+
+		go sendVerificationEmail(cfg, jwt, user.ID, req.Email)
+
+		response.RespondOK(w)
+	}
+}
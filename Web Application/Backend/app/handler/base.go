@@ -7,6 +7,8 @@ type CtxKey string
 const UserContextKey = CtxKey("user_ctx")
 
 type UserCtx struct {
-	ID   string
-	Role repository.UserRole
+	ID        string
+	OrgID     string
+	Role      repository.UserRole
+	SessionID string
 }
@@ -2,6 +2,7 @@ package competency
 
 import (
 	"encoding/json"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -26,6 +27,12 @@ type Competency struct {
 
 func Create(competencyRepository repository.CompetencyRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := Competency{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -34,6 +41,7 @@ func Create(competencyRepository repository.CompetencyRepository) http.HandlerFu
 
 		newCompetency := &repository.Competency{
 			ID:         uuid.NewString(),
+			OrgID:      userCred.OrgID,
 			Competency: req.Competency,
 			Description: req.Description,
 			Category:   req.Category,
@@ -2,6 +2,7 @@ package competency
 
 import (
 	"database/sql"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -11,8 +12,14 @@ import (
 
 func Delete(competencyRepository repository.CompetencyRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		competencyId := chi.URLParam(r, "id")
-		if err := competencyRepository.DeleteByID(r.Context(), competencyId); err != nil {
+		if err := competencyRepository.DeleteByID(r.Context(), userCred.OrgID, competencyId); err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Competency not found"))
 				return
@@ -1,6 +1,7 @@
 package competency
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -12,7 +13,13 @@ type GetAllCompetencyOnlyResponse struct {
 
 func GetAllCompetencyOnly(competencyRepository repository.CompetencyRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		competencies, err := competencyRepository.SelectAllCompetencyOnly(r.Context())
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		competencies, err := competencyRepository.SelectAllCompetencyOnly(r.Context(), userCred.OrgID)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
@@ -1,6 +1,7 @@
 package competency
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -14,8 +15,14 @@ type GetOneCompetencyResponse struct {
 
 func GetOne(competencyRepository repository.CompetencyRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		competencyId := chi.URLParam(r, "id")
-		competency, err := competencyRepository.SelectOneByID(r.Context(), competencyId)
+		competency, err := competencyRepository.SelectOneByID(r.Context(), userCred.OrgID, competencyId)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
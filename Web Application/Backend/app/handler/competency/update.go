@@ -3,6 +3,7 @@ package competency
 import (
 	"database/sql"
 	"encoding/json"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -13,6 +14,12 @@ import (
 
 func Update(competencyRepository repository.CompetencyRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := Competency{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -23,6 +30,7 @@ func Update(competencyRepository repository.CompetencyRepository) http.HandlerFu
 
 		updatedCompetency := &repository.Competency{
 			ID:         competencyId,
+			OrgID:      userCred.OrgID,
 			Competency: req.Competency,
 			Description: req.Description,
 			Category:   req.Category,
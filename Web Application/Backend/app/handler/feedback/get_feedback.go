@@ -1,11 +1,12 @@
 package feedback
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/language"
+	"interview/summarization/mlclient"
 	"interview/summarization/repository"
 	"net/http"
-	"encoding/json"
-	"io"
 	"fmt"
 )
 type Feedback struct {
@@ -21,41 +22,47 @@ type GetAllNeedFeedbackResponse struct {
 	Data []Feedback `json:"data"`
 }
 
-type ToLabelResponse struct {
-	Ids 		[]string `json:"id"`
-	Scores 	[]float64 `json:"scores"`
-}
-
-func GetAllNeedFeedback(feedbackRepository repository.FeedbackRepository, summarizationHost string) http.HandlerFunc {
+func GetAllNeedFeedback(
+	feedbackRepository repository.FeedbackRepository,
+	scorer mlclient.CompetencyScorer,
+	languages *language.Registry,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		isNoDataToLabel, err := feedbackRepository.IsNoDataToLabel(r.Context())
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		lang, ok := labelingLanguage(r, languages)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Language"))
+			return
+		}
+
+		isNoDataToLabel, err := feedbackRepository.IsNoDataToLabel(r.Context(), userCred.OrgID, lang)
 		if err != nil {
+			response.RespondError(w, response.InternalServerError())
 			return
 		}
 
 		if isNoDataToLabel {
 			fmt.Println("Get data to labeled")
-			url := summarizationHost + "/to-label"
-			resp,_ := http.Get(url)
-			if resp.StatusCode != http.StatusOK {
-				return
-			}
-			defer resp.Body.Close()
-			bodyLabel, _ := io.ReadAll(resp.Body)
-
-			res := ToLabelResponse{}
-			err := json.Unmarshal(bodyLabel, &res)
+			res, err := scorer.ToLabel(r.Context(), lang, userCred.OrgID)
 			if err != nil {
 				fmt.Println(err)
+				response.RespondError(w, response.InternalServerError())
+				return
 			}
 
-			err = feedbackRepository.UpdateBulkFeedback(r.Context(), res.Ids)
+			err = feedbackRepository.UpdateBulkFeedback(r.Context(), userCred.OrgID, res.IDs)
 			if err != nil {
+				response.RespondError(w, response.InternalServerError())
 				return
 			}
 		}
 
-		feedbacks, err := feedbackRepository.SelectByStatus(r.Context(), "TO_LABEL")
+		feedbacks, err := feedbackRepository.SelectByStatus(r.Context(), userCred.OrgID, "TO_LABEL", lang)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
@@ -0,0 +1,22 @@
+package feedback
+
+import (
+	"interview/summarization/language"
+	"net/http"
+)
+
+// labelingLanguage reads the language from the query string and falls back
+// to the first configured language that uses the labeling flow.
+func labelingLanguage(r *http.Request, languages *language.Registry) (string, bool) {
+	code := r.URL.Query().Get("language")
+	if code == "" {
+		return languages.Labeling()
+	}
+
+	l, ok := languages.Get(code)
+	if !ok || !l.Labeling {
+		return "", false
+	}
+
+	return l.Code, true
+}
@@ -1,7 +1,10 @@
 package feedback
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/language"
+	"interview/summarization/mlclient"
 	"interview/summarization/repository"
 	"net/http"
 	"encoding/json"
@@ -16,8 +19,24 @@ type FeedbackUpdate struct {
 	LabelFeedback string `json:"label_feedback"`
 }
 
-func UpdateFeedback(feedbackRepository repository.FeedbackRepository, summarizationHost string) http.HandlerFunc {
+func UpdateFeedback(
+	feedbackRepository repository.FeedbackRepository,
+	scorer mlclient.CompetencyScorer,
+	languages *language.Registry,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		lang, ok := labelingLanguage(r, languages)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Language"))
+			return
+		}
+
 		req := FeedbackUpdate{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -33,6 +52,7 @@ func UpdateFeedback(feedbackRepository repository.FeedbackRepository, summarizat
 
 		feedbackUpdate := &repository.Feedback{
 			ID: feedbackID,
+			OrgID: userCred.OrgID,
 			Status: status,
 			Transcript: req.Transkrip,
 			LabelFeedback: sql.NullString{
@@ -52,16 +72,17 @@ func UpdateFeedback(feedbackRepository repository.FeedbackRepository, summarizat
 		}
 
 		go func(ctx context.Context, fRepo repository.FeedbackRepository) {
-			isNoDataToLabel, _ := fRepo.IsNoDataToLabel(ctx)
+			isNoDataToLabel, err := fRepo.IsNoDataToLabel(ctx, userCred.OrgID, lang)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
 
 			if isNoDataToLabel {
 				fmt.Println("Train data")
-				url := summarizationHost + "/train"
-				resp,_ := http.Post(url, "application/json", nil)
-				if resp.StatusCode != http.StatusOK {
-					return
+				if err := scorer.Train(ctx, lang, userCred.OrgID); err != nil {
+					fmt.Println("failed to train model:", err)
 				}
-				defer resp.Body.Close()
 			}
 		}(context.Background(), feedbackRepository)
 		response.RespondOK(w)
@@ -0,0 +1,32 @@
+package language
+
+import (
+	"interview/summarization/app/response"
+	"interview/summarization/language"
+	"net/http"
+)
+
+type Language struct {
+	Code     string `json:"code"`
+	Labeling bool   `json:"labeling"`
+}
+
+type GetAllLanguageResponse struct {
+	Data []Language `json:"data"`
+}
+
+func GetAll(languages *language.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := GetAllLanguageResponse{
+			Data: []Language{},
+		}
+		for _, l := range languages.All() {
+			resp.Data = append(resp.Data, Language{
+				Code:     l.Code,
+				Labeling: l.Labeling,
+			})
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
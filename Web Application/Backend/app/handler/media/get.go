@@ -0,0 +1,106 @@
+package media
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/middleware"
+	"interview/summarization/app/response"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"interview/summarization/storage"
+	"interview/summarization/token"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Get serves a stored recording either to a signed link or to a logged in
+// user taking part in the room the recording belongs to.
+func Get(
+	roomRepository repository.RoomRepository,
+	permissions *permission.Table,
+	files storage.Storage,
+	jwt token.JWT,
+	auth func(http.Handler) http.Handler,
+) http.HandlerFunc {
+	authorized := auth(authorize(permissions, roomRepository, serve(files)))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !query.Has("signature") {
+			authorized.ServeHTTP(w, r)
+			return
+		}
+
+		if err := jwt.VerifyURL(r.URL.Path, query.Get("expires"), query.Get("signature")); err != nil {
+			response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+			return
+		}
+
+		serve(files).ServeHTTP(w, r)
+	}
+}
+
+// authorize lets through the participants of the room the answer was
+// recorded in, see middleware.RoomParticipant.
+func authorize(permissions *permission.Table, roomRepository repository.RoomRepository, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userCtx := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+
+		// answers are stored as answers/{roomId}/{questionId}/{file}
+		parts := strings.Split(chi.URLParam(r, "*"), "/")
+		if len(parts) != 4 || parts[0] != "answers" {
+			response.RespondError(w, response.ForbiddenError("Forbidden"))
+			return
+		}
+
+		owners, err := roomRepository.SelectOwnersByID(r.Context(), userCtx.OrgID, parts[1])
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if err != nil || !middleware.RoomParticipant(permissions, userCtx, owners) {
+			response.RespondError(w, response.ForbiddenError("Forbidden"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func serve(files storage.Storage) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "*")
+
+		file, err := files.Open(r.Context(), key)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				response.RespondError(w, response.NotFoundError("File not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Cache-Control", "private, no-store")
+		if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+
+		// local files can seek, which lets players request byte ranges
+		if seeker, ok := file.(io.ReadSeeker); ok {
+			http.ServeContent(w, r, path.Base(key), time.Time{}, seeker)
+			return
+		}
+
+		io.Copy(w, file)
+	})
+}
@@ -0,0 +1,58 @@
+package organization
+
+import (
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CreateRequest struct {
+	Name string `json:"name"`
+}
+
+type CreateResponse struct {
+	Data Organization `json:"data"`
+}
+
+// Create adds an organization, its first HRD is invited into it afterwards.
+func Create(organizationRepository repository.OrganizationRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := CreateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			response.RespondError(w, response.BadRequestError("Name is required"))
+			return
+		}
+
+		org := &repository.Organization{
+			ID:   uuid.NewString(),
+			Name: req.Name,
+		}
+		if err := organizationRepository.Insert(r.Context(), org); err != nil {
+			if errors.Is(err, repository.ErrOrganizationExists) {
+				response.RespondError(w, response.ConflictError("Organization already exists"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, CreateResponse{
+			Data: Organization{
+				ID:   org.ID,
+				Name: org.Name,
+			},
+		})
+	}
+}
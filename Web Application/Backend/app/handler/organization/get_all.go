@@ -0,0 +1,41 @@
+package organization
+
+import (
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+)
+
+type Organization struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+type GetAllResponse struct {
+	Data []Organization `json:"data"`
+}
+
+func GetAll(organizationRepository repository.OrganizationRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgs, err := organizationRepository.SelectAll(r.Context())
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetAllResponse{
+			Data: []Organization{},
+		}
+		for _, org := range orgs {
+			resp.Data = append(resp.Data, Organization{
+				ID:        org.ID,
+				Name:      org.Name,
+				CreatedAt: org.CreatedAt.Format(time.RFC3339),
+			})
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
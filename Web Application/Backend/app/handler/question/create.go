@@ -1,7 +1,9 @@
 package question
 
 import (
+	"database/sql"
 	"encoding/json"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -21,11 +23,18 @@ type Question struct {
 	OrgPosition	 	string `json:"org_position,omitempty"`
 	Transcript    string `json:"transcript,omitempty"`
 	StartAnswer		string `json:"start_answer,omitempty"`
+	IsLate				bool   `json:"is_late,omitempty"`
 	Labels				[]QuestionLabel `json:"labels,omitempty"`
 }
 
 func Create(questionRepository repository.QuestionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := Question{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -34,6 +43,7 @@ func Create(questionRepository repository.QuestionRepository) http.HandlerFunc {
 
 		newQuestion := &repository.Question{
 			ID:            uuid.NewString(),
+			OrgID:         userCred.OrgID,
 			Question:      req.Question,
 			DurationLimit: req.DurationLimit,
 			OrgPosition:   req.OrgPosition,
@@ -51,6 +61,11 @@ func Create(questionRepository repository.QuestionRepository) http.HandlerFunc {
 		}
 
 		if err := questionRepository.Insert(r.Context(), newQuestion, newLabels); err != nil {
+			if err == sql.ErrNoRows {
+				response.RespondError(w, response.NotFoundError("Competency not found"))
+				return
+			}
+
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
@@ -2,6 +2,7 @@ package question
 
 import (
 	"database/sql"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -11,8 +12,14 @@ import (
 
 func Delete(questionRepository repository.QuestionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		questionId := chi.URLParam(r, "id")
-		if err := questionRepository.DeleteByID(r.Context(), questionId); err != nil {
+		if err := questionRepository.DeleteByID(r.Context(), userCred.OrgID, questionId); err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Question not found"))
 				return
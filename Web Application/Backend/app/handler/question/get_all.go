@@ -1,6 +1,7 @@
 package question
 
 import (
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -12,7 +13,13 @@ type GetAllQuestionResponse struct {
 
 func GetAll(questionRepository repository.QuestionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		treatments, err := questionRepository.SelectAll(r.Context())
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		treatments, err := questionRepository.SelectAll(r.Context(), userCred.OrgID)
 		if err != nil {
 			response.RespondError(w, response.InternalServerError())
 			return
@@ -2,6 +2,7 @@ package question
 
 import (
 	"database/sql"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -15,8 +16,14 @@ type GetOneQuestionResponse struct {
 
 func GetOne(questionRepository repository.QuestionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		questionId := chi.URLParam(r, "id")
-		question, err := questionRepository.SelectOneByID(r.Context(), questionId)
+		question, err := questionRepository.SelectOneByID(r.Context(), userCred.OrgID, questionId)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Question not found"))
@@ -3,6 +3,7 @@ package question
 import (
 	"database/sql"
 	"encoding/json"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -13,6 +14,12 @@ import (
 
 func Update(questionRepository repository.QuestionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := Question{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -23,6 +30,7 @@ func Update(questionRepository repository.QuestionRepository) http.HandlerFunc {
 
 		updatedQuestion := &repository.Question{
 			ID:            questionId,
+			OrgID:         userCred.OrgID,
 			Question:      req.Question,
 			DurationLimit: req.DurationLimit,
 			OrgPosition:   req.OrgPosition,
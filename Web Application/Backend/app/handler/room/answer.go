@@ -1,18 +1,18 @@
 package room
 
 import (
-	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
-	"interview/summarization/repository"
-	"io"
 	"interview/summarization/config"
-
-
+	"interview/summarization/queue"
+	"interview/summarization/repository"
+	"log"
 	"net/http"
-	// "net/url"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,24 +20,23 @@ import (
 
 type AnswerReq struct {
 	AnswerURL string `json:"answer_url,omitempty"`
-	Language	string `json:"language,omitempty"`
-}
-
-type PredictResponse struct {
-	Scores [][]float64 `json:"scores,omitempty"`
 }
 
 func Answer(
 	roomRepository repository.RoomRepository,
-	competencyRepository repository.CompetencyRepository,
-	questionRepository repository.QuestionRepository,
-	feedbackRepository repository.FeedbackRepository,
+	attemptRepository repository.AttemptRepository,
+	jobRepository repository.JobRepository,
 	cfg config.Config,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		roomId := chi.URLParam(r, "roomId")
 		questionId := chi.URLParam(r, "questionId")
-		// validasi room has question
 
 		req := AnswerReq{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -45,105 +44,93 @@ func Answer(
 			return
 		}
 
-		go func(ctx context.Context, rRepo repository.RoomRepository, cRepo repository.CompetencyRepository, qRepo repository.QuestionRepository, fRepo repository.FeedbackRepository, roomId, questionId, fileLoc, language string) {
-			fmt.Println("running in the background")
-			payload := map[string]string{"link": fileLoc}
-			jsonData, err := json.Marshal(payload)
-			if err != nil {
-				panic(err)
-			}
-		
-			// Set the URL for the POST request
-			url := ""
-			if language == "ENGLISH" {
-				url = cfg.SpeechToTextHostEN + "/predict/english"
-			} else {
-				url = cfg.SpeechToTextHostID + "/predict/indonesian"
+		if req.AnswerURL == "" {
+			response.RespondError(w, response.BadRequestError("Answer URL is required"))
+			return
+		}
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
 			}
-		
-			// Create the POST request with JSON payload
-			resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-			defer resp.Body.Close()
-			bodySpeech, _ := io.ReadAll(resp.Body)
-
-			rRepo.InsertTranscript(ctx, roomId, questionId, fileLoc, string(bodySpeech))
-			if isAnswered, _ := rRepo.IsAnswered(ctx, roomId); isAnswered {
-				answer, _ := rRepo.GetResultQuestions(ctx, roomId)
-				questions, _ := qRepo.SelectAllByRoomID(ctx, roomId)
-				competencies, _ := cRepo.SelectAllByRoomID(ctx, roomId)
-
-				var mapKamus [][]string
-				var transcripts []string
-				for _, c := range competencies {
-					var mapLevel []string
-					transcript := ""
-					for _, q := range questions {
-						for _, ql := range q.Labels {
-							if ql.CompetencyID == c.ID {
-								transcript += answer[q.ID] + " "
-							}
-						}
-					}
-					for _, cl := range c.Levels {
-						mapLevel = append(mapLevel, cl.Description)
-					}
-					mapKamus = append(mapKamus, mapLevel)
-					transcripts = append(transcripts, transcript)
-				}
-
-				body, _ := json.Marshal(map[string]interface{}{
-					"transcripts": 			transcripts,
-					"competence_sets":  mapKamus,
-				})
-
-				url := ""
-				if language == "ENGLISH" {
-					url = cfg.SummarizationHostEN + "/predict"
-				} else {
-					url = cfg.SummarizationHostID + "/predict/laddernetwork"
-				}
-				
-				resp, _ := http.Post(url, "application/json", bytes.NewBuffer(body))
-				bodySummary, _ := io.ReadAll(resp.Body)
-
-				res := PredictResponse{}
-				err := json.Unmarshal(bodySummary, &res)
-				if err != nil {
-					fmt.Println(err)
-				}
-				// for result
-				var competencyRes []string
-				var levelRes []string
-				var resultRes []float64
-
-				// for feedback
-				var feedbackID []string
-				var competencyFeedback []string
-				var transcriptFeedback []string
-				var resultFeedback []string
-
-				for i, c := range competencies {
-					feedbackID = append(feedbackID, uuid.NewString())
-					competencyFeedback = append(competencyFeedback, c.ID)
-					transcriptFeedback = append(transcriptFeedback, transcripts[i])
-					maxIndex := 0
-					maxScore := -1.0
-					for j, cl := range c.Levels {
-						competencyRes = append(competencyRes, c.Competency)
-						levelRes = append(levelRes, cl.Level)
-						resultRes = append(resultRes, res.Scores[i][j])
-						if res.Scores[i][j] > maxScore {
-							maxScore = res.Scores[i][j]
-							maxIndex = j
-						}
-					}
-					resultFeedback = append(resultFeedback, c.Levels[maxIndex].ID)
-				}
-
-				rRepo.InsertResult(ctx, roomId, competencyRes, levelRes, resultRes)
-				fRepo.Insert(ctx, feedbackID, transcriptFeedback, competencyFeedback, resultFeedback, language)
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		question, err := roomRepository.GetOneQuestionByRoomID(r.Context(), roomId, questionId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Question not found"))
+				return
 			}
-		}(context.Background(), roomRepository, competencyRepository, questionRepository, feedbackRepository, roomId, questionId, req.AnswerURL, req.Language)
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		submittedAt := time.Now().UTC()
+		if err := checkRoomWindow(room, cfg, submittedAt); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		isLate, err := checkAnswerWindow(room, question, cfg, submittedAt)
+		if err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		attempt := &repository.AnswerAttempt{
+			ID:          uuid.NewString(),
+			RoomID:      roomId,
+			QuestionID:  questionId,
+			FileLink:    req.AnswerURL,
+			SubmittedAt: submittedAt,
+			IsLate:      isLate,
+		}
+		if err := attemptRepository.Submit(r.Context(), attempt, room.MaxAttempts, room.AttemptRule); err != nil {
+			response.RespondError(w, attemptError(err))
+			return
+		}
+
+		if err := enqueueTranscription(r.Context(), roomRepository, jobRepository, cfg, room, attempt); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		response.RespondOK(w)
 	}
 }
+
+// enqueueTranscription schedules the transcription of an attempt, the answer
+// is marked as failed when the job of the attempt that counts cannot be stored.
+func enqueueTranscription(
+	ctx context.Context,
+	roomRepository repository.RoomRepository,
+	jobRepository repository.JobRepository,
+	cfg config.Config,
+	room *repository.Room,
+	attempt *repository.AnswerAttempt,
+) error {
+	_, err := queue.Enqueue(ctx, jobRepository, repository.TranscribeAnswerJob, "transcribe:"+attempt.ID, cfg.JobMaxAttempts, queue.TranscribeAnswerPayload{
+		RoomID:     room.ID,
+		QuestionID: attempt.QuestionID,
+		AttemptID:  attempt.ID,
+		FileLink:   attempt.FileLink,
+		Language:   room.Language,
+	})
+	if err != nil {
+		if attempt.Selected {
+			statusErr := roomRepository.UpdateAnswerStatus(ctx, room.ID, attempt.QuestionID, repository.AnswerFailed, "failed to queue answer")
+			if statusErr != nil {
+				log.Printf("room: failed to record answer status of attempt %s: %v", attempt.ID, statusErr)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Attempt struct {
+	ID          string `json:"id"`
+	Attempt     int    `json:"attempt"`
+	FileLink    string `json:"file_link"`
+	Transcript  string `json:"transcript,omitempty"`
+	SubmittedAt string `json:"submitted_at"`
+	IsLate      bool   `json:"is_late"`
+	Selected    bool   `json:"selected"`
+}
+
+type GetAttemptsResponse struct {
+	Data []Attempt `json:"data"`
+}
+
+// attemptSettings defaults a room to a single attempt where the latest one
+// counts.
+func attemptSettings(req RoomCreate) (int, repository.AttemptRule, bool) {
+	maxAttempts := req.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+	if maxAttempts < 0 {
+		return 0, "", false
+	}
+
+	if req.AttemptRule == "" {
+		return maxAttempts, repository.AttemptLatest, true
+	}
+
+	rule, ok := repository.AttemptRuleMapper(req.AttemptRule)
+	return maxAttempts, rule, ok
+}
+
+func attemptError(err error) response.Error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return response.NotFoundError("Question not found")
+	case errors.Is(err, repository.ErrNoAttemptsLeft):
+		return response.ConflictError("No attempts left")
+	case errors.Is(err, repository.ErrInvalidTransition):
+		return response.ConflictError("Answer is already being processed")
+	}
+
+	return response.InternalServerError()
+}
+
+func GetAttempts(attemptRepository repository.AttemptRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		roomId := chi.URLParam(r, "roomId")
+		questionId := chi.URLParam(r, "questionId")
+
+		attempts, err := attemptRepository.SelectAllByQuestion(r.Context(), roomId, questionId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetAttemptsResponse{
+			Data: []Attempt{},
+		}
+		for _, attempt := range attempts {
+			resp.Data = append(resp.Data, Attempt{
+				ID:          attempt.ID,
+				Attempt:     attempt.Attempt,
+				FileLink:    attempt.FileLink,
+				Transcript:  attempt.Transcript.String,
+				SubmittedAt: attempt.SubmittedAt.Format(time.RFC3339),
+				IsLate:      attempt.IsLate,
+				Selected:    attempt.Selected,
+			})
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
+
+// ChooseAttempt lets the candidate pick which of their attempts is scored,
+// as long as the room is still open.
+func ChooseAttempt(
+	roomRepository repository.RoomRepository,
+	attemptRepository repository.AttemptRepository,
+	jobRepository repository.JobRepository,
+	cfg config.Config,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "roomId")
+		questionId := chi.URLParam(r, "questionId")
+		attemptId := chi.URLParam(r, "attemptId")
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := checkRoomWindow(room, cfg, time.Now().UTC()); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		attempt, err := attemptRepository.Choose(r.Context(), roomId, questionId, attemptId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Attempt not found"))
+				return
+			}
+
+			response.RespondError(w, attemptError(err))
+			return
+		}
+
+		if err := enqueueTranscription(r.Context(), roomRepository, jobRepository, cfg, room, attempt); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
@@ -0,0 +1,40 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func Cancel(roomRepository repository.RoomRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		if err := roomRepository.UpdateStatus(r.Context(), roomId, repository.Cancelled, userCred.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+			if errors.Is(err, repository.ErrInvalidTransition) {
+				response.RespondError(w, response.ConflictError("Room has already been closed"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"interview/summarization/app/handler/competency"
 	"interview/summarization/app/handler/question"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"interview/summarization/config"
+	"interview/summarization/language"
 	"net/http"
 	"net/smtp"
 	"os"
@@ -40,10 +42,14 @@ type Room struct {
 	InterviewerName  string                  `json:"interviewer_name,omitempty"`
 	Language				 string									 `json:"language,omitempty"`
 	PrepationTime		 int                     `json:"preparation_time,omitempty"`
+	ShuffleQuestions bool                    `json:"shuffle_questions"`
+	MaxAttempts      int                     `json:"max_attempts"`
+	AttemptRule      string                  `json:"attempt_rule,omitempty"`
 	QuestionsID      []string                `json:"questions_id,omitempty"`
 	CompetenciesID   []string                `json:"competencies_id,omitempty"`
 	Questions        []question.Question     `json:"questions"`
 	Competencies     []competency.Competency `json:"competencies"`
+	Result           *ResultSet              `json:"result,omitempty"`
 }
 
 type RoomCreate struct {
@@ -60,27 +66,47 @@ type RoomCreate struct {
 	IntervieweeEmail string		 	             `json:"interviewee_email,omitempty"`
 	Language				 string									 `json:"language,omitempty"`
 	PrepationTime		 int                     `json:"preparation_time,omitempty"`
+	ShuffleQuestions bool                    `json:"shuffle_questions"`
+	MaxAttempts      int                     `json:"max_attempts,omitempty"`
+	AttemptRule      string                  `json:"attempt_rule,omitempty"`
 	QuestionsID      []string                `json:"questions_id,omitempty"`
 	CompetenciesID   []string                `json:"competencies_id,omitempty"`
 	Questions        []question.Question     `json:"questions,omitempty"`
 	Competencies     []competency.Competency `json:"competencies,omitempty"`
 }
 
-func CreateRoom(roomRepository repository.RoomRepository, userRepository repository.UserRepository, cfg config.Config) http.HandlerFunc {
+func CreateRoom(roomRepository repository.RoomRepository, userRepository repository.UserRepository, languages *language.Registry, cfg config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := RoomCreate{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
 			return
 		}
 
+		if _, ok := languages.Get(req.Language); !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Language"))
+			return
+		}
+
+		maxAttempts, attemptRule, ok := attemptSettings(req)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Attempt Settings"))
+			return
+		}
+
 		status, ok := repository.RoomStatusMapper("WAITING ANSWER")
 		if !ok {
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
 
-		interviewee, err := userRepository.SelectIDByEmail(r.Context(), req.IntervieweeEmail)
+		interviewee, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, req.IntervieweeEmail)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				response.RespondError(w, response.NotFoundError("User not found"))
@@ -91,7 +117,7 @@ func CreateRoom(roomRepository repository.RoomRepository, userRepository reposit
 			return
 		}
 
-		interviewer, err := userRepository.SelectIDByEmail(r.Context(), req.InterviewerEmail)
+		interviewer, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, req.InterviewerEmail)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				response.RespondError(w, response.NotFoundError("User not found"))
@@ -102,8 +128,19 @@ func CreateRoom(roomRepository repository.RoomRepository, userRepository reposit
 			return
 		}
 
+		if _, err := roomRepository.SelectRoomGroupByID(r.Context(), userCred.OrgID, req.RoomGroupID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room group not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		newRoom := &repository.Room{
 			ID:            uuid.NewString(),
+			OrgID:         userCred.OrgID,
 			InterviewerID: interviewer.ID,
 			RoomGroupID:   req.RoomGroupID,
 			Title:         req.Title,
@@ -113,6 +150,9 @@ func CreateRoom(roomRepository repository.RoomRepository, userRepository reposit
 			Status:        status,
 			Language:			 req.Language,
 			PrepationTime: req.PrepationTime,
+			ShuffleQuestions: req.ShuffleQuestions,
+			MaxAttempts:   maxAttempts,
+			AttemptRule:   attemptRule,
 		}
 
 		go func(ctx context.Context, room repository.Room, interviewer, interviewee repository.User) {
@@ -184,7 +224,12 @@ func CreateRoom(roomRepository repository.RoomRepository, userRepository reposit
 			}
 		}(context.Background(), *newRoom, *interviewer, *interviewee)
 
-		if err := roomRepository.Insert(r.Context(), newRoom, req.QuestionsID, req.CompetenciesID); err != nil {
+		if err := roomRepository.Insert(r.Context(), newRoom, orderQuestions(req.QuestionsID, newRoom.ShuffleQuestions, newRoom.ID, interviewee.ID), req.CompetenciesID, userCred.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Question or competency not found"))
+				return
+			}
+
 			fmt.Println(err)
 			response.RespondError(w, response.InternalServerError())
 			return
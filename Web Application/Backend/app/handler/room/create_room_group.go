@@ -4,9 +4,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"interview/summarization/config"
+	"interview/summarization/language"
 	"net/http"
 	"net/smtp"
 	"os"
@@ -42,21 +44,38 @@ func getInitials(name string) string {
 	return string(initials)
 }
 
-func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository repository.UserRepository, cfg config.Config) http.HandlerFunc {
+func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository repository.UserRepository, languages *language.Registry, cfg config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := RoomGroupsCreate{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
 			return
 		}
 
+		if _, ok := languages.Get(req.Room.Language); !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Language"))
+			return
+		}
+
+		maxAttempts, attemptRule, ok := attemptSettings(req.Room)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Attempt Settings"))
+			return
+		}
+
 		status, ok := repository.RoomStatusMapper("WAITING ANSWER")
 		if !ok {
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
 
-		interviewer, err := userRepository.SelectIDByEmail(r.Context(), req.Room.InterviewerEmail)
+		interviewer, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, req.Room.InterviewerEmail)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				response.RespondError(w, response.NotFoundError("User not found"))
@@ -68,7 +87,7 @@ func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository re
 		}
 
 		for i, email := range req.IntervieweeEmail {
-			interviewee, err := userRepository.SelectIDByEmail(r.Context(), email)
+			interviewee, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, email)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
 					response.RespondError(w, response.NotFoundError("User not found"))
@@ -83,6 +102,7 @@ func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository re
 			title := "[" + strconv.Itoa(i+1) + "]_" + initials + "_" + req.OrgPosition + "_" + interviewee.Name
 			newRoomGroup := &repository.RoomGroup{
 				ID:            uuid.NewString(),
+				OrgID:         userCred.OrgID,
 				Title:         title,
 				OrgPosition:   req.OrgPosition,
 				IntervieweeID: interviewee.ID,
@@ -90,6 +110,7 @@ func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository re
 
 			newRoom := &repository.Room{
 				ID:            uuid.NewString(),
+				OrgID:         userCred.OrgID,
 				InterviewerID: interviewer.ID,
 				RoomGroupID:   newRoomGroup.ID,
 				Title:         req.Room.Title,
@@ -99,6 +120,9 @@ func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository re
 				Status:        status,
 				Language:			 req.Room.Language,
 				PrepationTime: req.Room.PrepationTime,
+				ShuffleQuestions: req.Room.ShuffleQuestions,
+				MaxAttempts:   maxAttempts,
+				AttemptRule:   attemptRule,
 			}
 
 			go func(ctx context.Context, room repository.Room, interviewer, interviewee repository.User) {
@@ -175,7 +199,12 @@ func CreateRoomGroup(roomRepository repository.RoomRepository, userRepository re
 				response.RespondError(w, response.InternalServerError())
 				return
 			}
-			if err := roomRepository.Insert(r.Context(), newRoom, req.Room.QuestionsID, req.Room.CompetenciesID); err != nil {
+			if err := roomRepository.Insert(r.Context(), newRoom, orderQuestions(req.Room.QuestionsID, newRoom.ShuffleQuestions, newRoom.ID, interviewee.ID), req.Room.CompetenciesID, userCred.ID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.RespondError(w, response.NotFoundError("Question or competency not found"))
+					return
+				}
+
 				fmt.Println(err)
 				response.RespondError(w, response.InternalServerError())
 				return
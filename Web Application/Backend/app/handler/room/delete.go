@@ -2,6 +2,7 @@ package room
 
 import (
 	"database/sql"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -11,8 +12,14 @@ import (
 
 func Delete(roomRepository repository.RoomRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		roomId := chi.URLParam(r, "id")
-		room, err := roomRepository.SelectOneRoomByID(r.Context(), roomId)
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Room not found"))
@@ -23,7 +30,7 @@ func Delete(roomRepository repository.RoomRepository) http.HandlerFunc {
 			return
 		}
 		roomGroupId := room.RoomGroupID
-		if err := roomRepository.DeleteByID(r.Context(), roomId, roomGroupId); err != nil {
+		if err := roomRepository.DeleteByID(r.Context(), userCred.OrgID, roomId, roomGroupId); err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Room not found"))
 				return
@@ -2,17 +2,44 @@ package room
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/config"
 	"interview/summarization/repository"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func FinishAnswer(roomRepository repository.RoomRepository) http.HandlerFunc {
+func FinishAnswer(roomRepository repository.RoomRepository, cfg config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		roomId := chi.URLParam(r, "roomId")
 
+		current, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := checkRoomWindow(current, cfg, time.Now().UTC()); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
 		status, ok := repository.RoomStatusMapper("WAITING REVIEW")
 		if !ok {
 			response.RespondError(w, response.InternalServerError())
@@ -24,11 +51,16 @@ func FinishAnswer(roomRepository repository.RoomRepository) http.HandlerFunc {
 			Status:    	status,
 		}
 
-		if err := roomRepository.UpdateStatusAndSubmission(context.Background(), room); err != nil {
+		if err := roomRepository.UpdateStatusAndSubmission(context.Background(), room, userCred.ID); err != nil {
+			if errors.Is(err, repository.ErrInvalidTransition) {
+				response.RespondError(w, response.ConflictError("Room has not been started"))
+				return
+			}
+
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
 
 		response.RespondOK(w)
 	}
-}	
\ No newline at end of file
+}
@@ -3,6 +3,7 @@ package room
 import (
 	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/permission"
 	"interview/summarization/repository"
 	"net/http"
 	"fmt"
@@ -15,6 +16,17 @@ type RoomResponse struct {
 	End             string `json:"end"`
 	Submission      string `json:"submission"`
 	Status          string `json:"status"`
+	TimeToSubmit    *int64 `json:"time_to_submit,omitempty"`
+	TimeToReview    *int64 `json:"time_to_review,omitempty"`
+}
+
+// RoomGroupSummary averages, in seconds, how long candidates took from
+// starting a room to submitting it and how long reviewers took afterwards.
+type RoomGroupSummary struct {
+	Submitted       int    `json:"submitted"`
+	Reviewed        int    `json:"reviewed"`
+	AvgTimeToSubmit *int64 `json:"avg_time_to_submit,omitempty"`
+	AvgTimeToReview *int64 `json:"avg_time_to_review,omitempty"`
 }
 
 type RoomGroupResponse struct {
@@ -25,13 +37,16 @@ type RoomGroupResponse struct {
 	IntervieweeEmail string 				`json:"interviewee_email"`
 	IntervieweePhone string 				`json:"interviewee_phone"`
 	Room						 []RoomResponse `json:"room"`
+	Summary					 *RoomGroupSummary `json:"summary,omitempty"`
 }
 
 type GetAllRoomGroupResponse struct {
 	Data []RoomGroupResponse `json:"data"`
 }
 
-func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc {
+// GetAllRoomGroup lists every room group of the organization to the users
+// overseeing every room, and to the others the groups they take part in.
+func GetAllRoomGroup(roomRepository repository.RoomRepository, permissions *permission.Table) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
 		if !ok {
@@ -43,8 +58,8 @@ func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc
 			Data: []RoomGroupResponse{},
 		}
 
-		if userCred.Role == repository.Hrd {
-			roomGroups, err := roomRepository.SelectAllRoomGroup(r.Context())
+		if permissions.Has(userCred.Role, permission.RoomOversee) {
+			roomGroups, err := roomRepository.SelectAllRoomGroup(r.Context(), userCred.OrgID)
 			if err != nil {
 				fmt.Println(err)
 				response.RespondError(w, response.InternalServerError())
@@ -52,7 +67,7 @@ func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc
 			}
 
 			for _, roomGroup := range roomGroups {
-				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), roomGroup.ID)
+				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), userCred.OrgID, roomGroup.ID)
 				if err != nil {
 					fmt.Println(err)
 					response.RespondError(w, response.InternalServerError())
@@ -86,8 +101,8 @@ func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc
 					Room:             roomResponse,
 				})
 			}
-		} else if userCred.Role == repository.Interviewer {
-			roomGroups, err := roomRepository.SelectAllRoomGroupByInterviewerID(r.Context(), userCred.ID)
+		} else if userCred.Role != repository.Interviewee {
+			roomGroups, err := roomRepository.SelectAllRoomGroupByInterviewerID(r.Context(), userCred.OrgID, userCred.ID)
 			if err != nil {
 				fmt.Println(err)
 				response.RespondError(w, response.InternalServerError())
@@ -95,7 +110,7 @@ func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc
 			}
 
 			for _, roomGroup := range roomGroups {
-				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), roomGroup.ID)
+				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), userCred.OrgID, roomGroup.ID)
 				if err != nil {
 					fmt.Println(err)
 					response.RespondError(w, response.InternalServerError())
@@ -130,14 +145,14 @@ func GetAllRoomGroup(roomRepository repository.RoomRepository) http.HandlerFunc
 				})
 			}
 		} else if userCred.Role == repository.Interviewee {
-			roomGroups, err := roomRepository.SelectAllRoomGroupByIntervieweeID(r.Context(), userCred.ID)
+			roomGroups, err := roomRepository.SelectAllRoomGroupByIntervieweeID(r.Context(), userCred.OrgID, userCred.ID)
 			if err != nil {
 				response.RespondError(w, response.InternalServerError())
 				return
 			}
 
 			for _, roomGroup := range roomGroups {
-				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), roomGroup.ID)
+				rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), userCred.OrgID, roomGroup.ID)
 				if err != nil {
 					response.RespondError(w, response.InternalServerError())
 					return
@@ -6,6 +6,7 @@ import (
 	"interview/summarization/repository"
 	"net/http"
 	"fmt"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -37,7 +38,7 @@ func GetOneQuestionRoom(roomRepository repository.RoomRepository) http.HandlerFu
 
 		startAnswer := "-"
 		if question.StartAnswer.Valid {
-			startAnswer = question.StartAnswer.String
+			startAnswer = question.StartAnswer.Time.Format(time.RFC3339)
 		}
 
 		response.Respond(w, http.StatusOK, GetOneQuestionRoomResponse{
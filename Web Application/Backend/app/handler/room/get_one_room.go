@@ -7,8 +7,10 @@ import (
 	"interview/summarization/app/handler/competency"
 	"interview/summarization/app/handler/question"
 	"interview/summarization/app/response"
+	"interview/summarization/config"
 	"interview/summarization/repository"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -21,6 +23,7 @@ func GetOneRoom(
 	roomRepository repository.RoomRepository,
 	questionRepository repository.QuestionRepository,
 	competencyRepository repository.CompetencyRepository,
+	cfg config.Config,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		roomId := chi.URLParam(r, "id")
@@ -30,7 +33,7 @@ func GetOneRoom(
 			return
 		}
 
-		room, err := roomRepository.SelectOneRoomByID(r.Context(), roomId)
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
 		if err != nil {
 			fmt.Println(err)
 			if err == sql.ErrNoRows {
@@ -73,6 +76,9 @@ func GetOneRoom(
 				Note:        			note,
 				Language:					room.Language,
 				PrepationTime:		room.PrepationTime,
+				ShuffleQuestions:	room.ShuffleQuestions,
+				MaxAttempts:			room.MaxAttempts,
+				AttemptRule:			string(room.AttemptRule),
 			},
 		}
 
@@ -82,6 +88,11 @@ func GetOneRoom(
 			return
 		}
 
+		// candidates only get to see the questions while the room is open
+		if userCred.Role == repository.Interviewee && checkRoomWindow(room, cfg, time.Now().UTC()) != nil {
+			questions = nil
+		}
+
 		resp.Data.Questions = []question.Question{}
 		for _, qt := range questions {
 			resp.Data.Questions = append(resp.Data.Questions, question.Question{
@@ -94,7 +105,7 @@ func GetOneRoom(
 		if userCred.Role == repository.Interviewer || userCred.Role == repository.Hrd {
 			var resultCompetency repository.ResultCompetency
 			var resultQuestion repository.ResultQuestion
-			if resp.Data.Status != "WAITING ANSWER" {
+			if !room.Status.IsOpen() {
 				resultCompetency, err = roomRepository.GetResultCompetencies(r.Context(), roomId)
 				if err != nil {
 					fmt.Println(err)
@@ -108,6 +119,17 @@ func GetOneRoom(
 					response.RespondError(w, response.InternalServerError())
 					return
 				}
+
+				resultSet, err := roomRepository.SelectLatestResultSet(r.Context(), roomId)
+				if err != nil && err != sql.ErrNoRows {
+					fmt.Println(err)
+					response.RespondError(w, response.InternalServerError())
+					return
+				}
+				if resultSet != nil {
+					result := newResultSet(resultSet)
+					resp.Data.Result = &result
+				}
 			}
 
 			competencies, err := competencyRepository.SelectAllByRoomID(r.Context(), roomId)
@@ -143,8 +165,21 @@ func GetOneRoom(
 			}
 
 			if len(resultQuestion) > 0 {
+				answers, err := roomRepository.SelectAnswerProcessing(r.Context(), roomId)
+				if err != nil {
+					fmt.Println(err)
+					response.RespondError(w, response.InternalServerError())
+					return
+				}
+
+				isLate := map[string]bool{}
+				for _, answer := range answers {
+					isLate[answer.QuestionID] = answer.IsLate
+				}
+
 				for idx, question := range resp.Data.Questions {
 					question.Transcript = resultQuestion[question.ID]
+					question.IsLate = isLate[question.ID]
 					resp.Data.Questions[idx] = question
 				}
 			}
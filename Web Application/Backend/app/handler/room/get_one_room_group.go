@@ -1,6 +1,10 @@
 package room
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -12,19 +16,67 @@ type GetOneRoomGroupResponse struct {
 	Data RoomGroupResponse `json:"data"`
 }
 
+// secondsBetween returns the seconds from one milestone to the next, or nil
+// when either of them has not been reached.
+func secondsBetween(from, to sql.NullTime) *int64 {
+	if !from.Valid || !to.Valid {
+		return nil
+	}
+
+	seconds := int64(to.Time.Sub(from.Time).Seconds())
+	return &seconds
+}
+
+func average(total int64, count int) *int64 {
+	if count == 0 {
+		return nil
+	}
+
+	avg := total / int64(count)
+	return &avg
+}
+
 func GetOneRoomGroup(
 	roomRepository repository.RoomRepository,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		roomGroupId := chi.URLParam(r, "id")
 
-		roomGroup, err := roomRepository.SelectRoomGroupByID(r.Context(), roomGroupId)
-		rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), roomGroupId)
+		roomGroup, err := roomRepository.SelectRoomGroupByID(r.Context(), userCred.OrgID, roomGroupId)
 		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room group not found"))
+				return
+			}
+
 			response.RespondError(w, response.InternalServerError())
 			return
 		}
 
+		rooms, err := roomRepository.SelectAllRoomByGroupID(r.Context(), userCred.OrgID, roomGroupId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		milestones, err := roomRepository.SelectMilestonesByGroupID(r.Context(), roomGroupId)
+		if err != nil {
+			fmt.Println(err)
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		milestoneByRoom := map[string]*repository.RoomMilestones{}
+		for _, milestone := range milestones {
+			milestoneByRoom[milestone.RoomID] = milestone
+		}
+
 		resp := GetOneRoomGroupResponse{
 			Data: RoomGroupResponse{
 				ID:               roomGroup.ID,
@@ -37,6 +89,8 @@ func GetOneRoomGroup(
 			},
 		}
 
+		summary := &RoomGroupSummary{}
+		var totalSubmit, totalReview int64
 		for _, room := range rooms {
 			submission := "-"
 			if room.Submission.Valid {
@@ -52,9 +106,26 @@ func GetOneRoomGroup(
 				Status:          string(room.Status),
 			}
 
+			if milestone, ok := milestoneByRoom[room.ID]; ok {
+				roomResponse.TimeToSubmit = secondsBetween(milestone.StartedAt, milestone.SubmittedAt)
+				roomResponse.TimeToReview = secondsBetween(milestone.SubmittedAt, milestone.ReviewedAt)
+			}
+			if roomResponse.TimeToSubmit != nil {
+				summary.Submitted++
+				totalSubmit += *roomResponse.TimeToSubmit
+			}
+			if roomResponse.TimeToReview != nil {
+				summary.Reviewed++
+				totalReview += *roomResponse.TimeToReview
+			}
+
 			resp.Data.Room = append(resp.Data.Room, roomResponse)
 		}
 
+		summary.AvgTimeToSubmit = average(totalSubmit, summary.Submitted)
+		summary.AvgTimeToReview = average(totalReview, summary.Reviewed)
+		resp.Data.Summary = summary
+
 		response.Respond(w, http.StatusOK, resp)
 	}
 }
@@ -0,0 +1,88 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AnswerProcessing struct {
+	QuestionID      string `json:"question_id"`
+	Question        string `json:"question"`
+	Status          string `json:"status"`
+	LastError       string `json:"last_error,omitempty"`
+	QueuedAt        string `json:"queued_at,omitempty"`
+	TranscribedAt   string `json:"transcribed_at,omitempty"`
+	ScoredAt        string `json:"scored_at,omitempty"`
+	FailedAt        string `json:"failed_at,omitempty"`
+	StatusUpdatedAt string `json:"status_updated_at,omitempty"`
+	SubmittedAt     string `json:"submitted_at,omitempty"`
+	IsLate          bool   `json:"is_late"`
+}
+
+type GetProcessingResponse struct {
+	Data []AnswerProcessing `json:"data"`
+}
+
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.Format(time.RFC3339)
+}
+
+func GetProcessing(roomRepository repository.RoomRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		if _, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		answers, err := roomRepository.SelectAnswerProcessing(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetProcessingResponse{
+			Data: []AnswerProcessing{},
+		}
+		for _, answer := range answers {
+			resp.Data = append(resp.Data, AnswerProcessing{
+				QuestionID:      answer.QuestionID,
+				Question:        answer.Question,
+				Status:          string(answer.Status),
+				LastError:       answer.LastError.String,
+				QueuedAt:        formatNullTime(answer.QueuedAt),
+				TranscribedAt:   formatNullTime(answer.TranscribedAt),
+				ScoredAt:        formatNullTime(answer.ScoredAt),
+				FailedAt:        formatNullTime(answer.FailedAt),
+				StatusUpdatedAt: formatNullTime(answer.StatusUpdatedAt),
+				SubmittedAt:     formatNullTime(answer.SubmittedAt),
+				IsLate:          answer.IsLate,
+			})
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
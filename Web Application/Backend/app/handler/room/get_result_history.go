@@ -0,0 +1,78 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ResultSet struct {
+	ID             string                        `json:"id"`
+	ModelVersion   string                        `json:"model_version,omitempty"`
+	Language       string                        `json:"language"`
+	TranscriptHash string                        `json:"transcript_hash"`
+	CreatedAt      string                        `json:"created_at"`
+	Results        map[string]map[string]float64 `json:"results,omitempty"`
+}
+
+type GetResultHistoryResponse struct {
+	Data []ResultSet `json:"data"`
+}
+
+func newResultSet(resultSet *repository.ResultSet) ResultSet {
+	return ResultSet{
+		ID:             resultSet.ID,
+		ModelVersion:   resultSet.ModelVersion.String,
+		Language:       resultSet.Language,
+		TranscriptHash: resultSet.TranscriptHash,
+		CreatedAt:      resultSet.CreatedAt.Format(time.RFC3339),
+		Results:        resultSet.Results,
+	}
+}
+
+// GetResultHistory lists every scoring run of the room, newest first, so
+// runs before and after a model retrain can be compared.
+func GetResultHistory(roomRepository repository.RoomRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		if _, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resultSets, err := roomRepository.SelectResultSets(r.Context(), roomId)
+		if err != nil {
+			fmt.Println(err)
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetResultHistoryResponse{
+			Data: []ResultSet{},
+		}
+		for _, resultSet := range resultSets {
+			resp.Data = append(resp.Data, newResultSet(resultSet))
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
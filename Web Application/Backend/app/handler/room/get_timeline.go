@@ -0,0 +1,76 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RoomStatusEvent struct {
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	Note       string `json:"note,omitempty"`
+	ActorID    string `json:"actor_id,omitempty"`
+	ActorName  string `json:"actor_name,omitempty"`
+	ActorEmail string `json:"actor_email,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type GetTimelineResponse struct {
+	Data []RoomStatusEvent `json:"data"`
+}
+
+func GetTimeline(roomRepository repository.RoomRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		if _, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		events, err := roomRepository.SelectStatusEvents(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetTimelineResponse{
+			Data: []RoomStatusEvent{},
+		}
+		for _, event := range events {
+			item := RoomStatusEvent{
+				FromStatus: event.FromStatus.String,
+				ToStatus:   string(event.ToStatus),
+				Note:       event.Note.String,
+				CreatedAt:  event.CreatedAt.Format(time.RFC3339),
+			}
+			if event.Actor != nil {
+				item.ActorID = event.Actor.ID
+				item.ActorName = event.Actor.Name
+				item.ActorEmail = event.Actor.Email
+			}
+
+			resp.Data = append(resp.Data, item)
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,26 @@
+package room
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// orderQuestions returns the questions in the order the candidate gets them.
+// A shuffled room is seeded with the room and the candidate, so the order is
+// different for every candidate but stays the same across reloads.
+func orderQuestions(questionIds []string, shuffle bool, roomId, intervieweeId string) []string {
+	ordered := append([]string{}, questionIds...)
+	if !shuffle {
+		return ordered
+	}
+
+	seed := fnv.New64a()
+	seed.Write([]byte(roomId + ":" + intervieweeId))
+
+	rnd := rand.New(rand.NewSource(int64(seed.Sum64())))
+	rnd.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+
+	return ordered
+}
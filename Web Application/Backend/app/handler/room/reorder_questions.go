@@ -0,0 +1,81 @@
+package room
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReorderQuestionsReq struct {
+	QuestionsID []string `json:"questions_id"`
+}
+
+func ReorderQuestions(roomRepository repository.RoomRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		req := ReorderQuestionsReq{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		// current_question points into the order, it can't move under a candidate
+		if room.Status != repository.WaitingAnswer {
+			response.RespondError(w, response.ConflictError("Room has already been started"))
+			return
+		}
+
+		questions, err := roomRepository.SelectQuestionsInRoom(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		inRoom := map[string]bool{}
+		for _, question := range questions {
+			inRoom[question.ID] = true
+		}
+		if len(req.QuestionsID) != len(questions) {
+			response.RespondError(w, response.BadRequestError("Every question of the room must be listed once"))
+			return
+		}
+		for _, id := range req.QuestionsID {
+			if !inRoom[id] {
+				response.RespondError(w, response.BadRequestError("Every question of the room must be listed once"))
+				return
+			}
+			delete(inRoom, id)
+		}
+
+		if err := roomRepository.ReorderQuestions(r.Context(), roomId, req.QuestionsID); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
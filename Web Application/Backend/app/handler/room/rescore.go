@@ -0,0 +1,71 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/queue"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Rescore queues a new scoring run of the room from its stored transcripts.
+// The previous results are kept as history.
+func Rescore(
+	roomRepository repository.RoomRepository,
+	jobRepository repository.JobRepository,
+	cfg config.Config,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		isAnswered, err := roomRepository.IsAnswered(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !isAnswered {
+			response.RespondError(w, response.ConflictError("Room has unanswered questions"))
+			return
+		}
+
+		queued, err := queue.Enqueue(r.Context(), jobRepository, repository.ScoreRoomJob, "score:"+roomId, cfg.JobMaxAttempts, queue.ScoreRoomPayload{
+			RoomID:   roomId,
+			Language: room.Language,
+			Rescore:  true,
+		})
+		if err != nil {
+			fmt.Println(err)
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if !queued {
+			response.RespondError(w, response.ConflictError("Scoring already in progress"))
+			return
+		}
+
+		response.Respond(w, http.StatusAccepted, response.CommonMessage{Message: "Rescoring queued"})
+	}
+}
@@ -3,6 +3,7 @@ package room
 import (
 	"database/sql"
 	"encoding/json"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
 	"interview/summarization/repository"
 	"net/http"
@@ -17,6 +18,12 @@ type ReviewRoom struct {
 
 func Review(roomRepository repository.RoomRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		req := ReviewRoom{}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
@@ -26,7 +33,7 @@ func Review(roomRepository repository.RoomRepository) http.HandlerFunc {
 		roomId := chi.URLParam(r, "id")
 
 		status, ok := repository.RoomStatusMapper(req.Status)
-		if !ok {
+		if !ok || (status != repository.Accepted && status != repository.Rejected && status != repository.Completed) {
 			response.RespondError(w, response.BadRequestError("Invalid Status"))
 			return
 		}
@@ -38,11 +45,15 @@ func Review(roomRepository repository.RoomRepository) http.HandlerFunc {
 				String: req.Note,
 			},
 		}
-		if err := roomRepository.Review(r.Context(), room); err != nil {
+		if err := roomRepository.Review(r.Context(), room, userCred.ID); err != nil {
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Room not found"))
 				return
 			}
+			if err == repository.ErrInvalidTransition {
+				response.RespondError(w, response.ConflictError("Room is not waiting for review"))
+				return
+			}
 
 			response.RespondError(w, response.InternalServerError())
 			return
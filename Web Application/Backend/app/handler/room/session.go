@@ -0,0 +1,187 @@
+package room
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	SessionNotStarted = "NOT_STARTED"
+	SessionPreparing  = "PREPARATION"
+	SessionAnswering  = "ANSWERING"
+	SessionFinished   = "FINISHED"
+)
+
+type Session struct {
+	RoomID               string           `json:"room_id"`
+	Status               string           `json:"status"`
+	Phase                string           `json:"phase"`
+	CurrentQuestion      int              `json:"current_question"`
+	TotalQuestions       int              `json:"total_questions"`
+	Question             *OneQuestionRoom `json:"question,omitempty"`
+	PreparationRemaining int64            `json:"preparation_remaining"`
+	AnswerRemaining      int64            `json:"answer_remaining"`
+	RoomRemaining        int64            `json:"room_remaining"`
+	Answered             []string         `json:"answered"`
+	Missed               []string         `json:"missed"`
+}
+
+type SessionResponse struct {
+	Data Session `json:"data"`
+}
+
+func remainingSeconds(until, now time.Time) int64 {
+	if !until.After(now) {
+		return 0
+	}
+
+	return int64(until.Sub(now).Seconds())
+}
+
+// StartSession lets a candidate join or rejoin a room. The next question is
+// worked out from the stored answers, so calling it again after a crash or a
+// reload returns the same question with whatever time is left on it.
+func StartSession(
+	roomRepository repository.RoomRepository,
+	questionRepository repository.QuestionRepository,
+	cfg config.Config,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "id")
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		now := time.Now().UTC()
+		if err := checkRoomWindow(room, cfg, now); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		if err := roomRepository.UpdateStatus(r.Context(), roomId, repository.InProgress, userCred.ID); err != nil {
+			if errors.Is(err, repository.ErrInvalidTransition) {
+				response.RespondError(w, response.ConflictError("Room is closed"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		// the order of the room questions is the one the candidate is shown
+		questions, err := questionRepository.SelectAllByRoomID(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		answers, err := roomRepository.SelectQuestionsInRoom(r.Context(), roomId)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		answerByQuestion := map[string]*repository.QuestionInRoom{}
+		for _, answer := range answers {
+			answerByQuestion[answer.ID] = answer
+		}
+
+		session := Session{
+			RoomID:          roomId,
+			Status:          string(repository.InProgress),
+			Phase:           SessionFinished,
+			CurrentQuestion: len(questions),
+			TotalQuestions:  len(questions),
+			Answered:        []string{},
+			Missed:          []string{},
+		}
+
+		end, err := time.Parse(time.RFC3339Nano, room.End)
+		if err != nil {
+			fmt.Println(err)
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		session.RoomRemaining = remainingSeconds(end, now)
+
+		for idx, qt := range questions {
+			answer, ok := answerByQuestion[qt.ID]
+			if !ok {
+				continue
+			}
+
+			if answer.AnswerStatus != repository.AnswerNotSubmitted {
+				session.Answered = append(session.Answered, qt.ID)
+				continue
+			}
+
+			// a question started earlier is only missed once its grace period is over
+			if _, err := checkAnswerWindow(room, answer, cfg, now); errors.Is(err, errAnswerClosed) {
+				session.Missed = append(session.Missed, qt.ID)
+				continue
+			}
+
+			if session.Question != nil {
+				continue
+			}
+
+			session.CurrentQuestion = idx
+			session.Question = &OneQuestionRoom{
+				ID:            answer.ID,
+				Question:      answer.Question,
+				DurationLimit: answer.DurationLimit,
+				StartAnswer:   "-",
+			}
+
+			preparation := time.Duration(room.PrepationTime) * time.Second
+			duration := time.Duration(answer.DurationLimit) * time.Minute
+			if !answer.StartAnswer.Valid {
+				session.Phase = SessionNotStarted
+				session.PreparationRemaining = int64(preparation.Seconds())
+				session.AnswerRemaining = int64(duration.Seconds())
+				continue
+			}
+
+			session.Question.StartAnswer = answer.StartAnswer.Time.Format(time.RFC3339)
+			answerStart := answer.StartAnswer.Time.Add(preparation)
+			session.Phase = SessionAnswering
+			session.AnswerRemaining = remainingSeconds(answerStart.Add(duration), now)
+			if now.Before(answerStart) {
+				session.Phase = SessionPreparing
+				session.PreparationRemaining = remainingSeconds(answerStart, now)
+				session.AnswerRemaining = int64(duration.Seconds())
+			}
+		}
+
+		if err := roomRepository.UpdateRoomQuestionCond(r.Context(), roomId, session.CurrentQuestion, true); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, SessionResponse{
+			Data: session,
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
   "database/sql"
   "encoding/json"
   "errors"
+  "interview/summarization/app/handler"
+  "interview/summarization/app/middleware"
   "interview/summarization/app/response"
+  "interview/summarization/permission"
   "interview/summarization/repository"
   "interview/summarization/config"
   "net/http"
@@ -19,21 +22,44 @@ import (
 )
 
 
-func UpdateQuestionsAndCompetenciesRoom(roomRepository repository.RoomRepository, userRepository repository.UserRepository, cfg config.Config) http.HandlerFunc {
+func UpdateQuestionsAndCompetenciesRoom(roomRepository repository.RoomRepository, userRepository repository.UserRepository, permissions *permission.Table, cfg config.Config) http.HandlerFunc {
   return func(w http.ResponseWriter, r *http.Request) {
+    userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+    if !ok {
+      response.RespondError(w, response.InternalServerError())
+      return
+    }
+
     req := RoomCreate{}
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
       response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
       return
     }
 
+    // the room comes in the body, so this is the check roomAccess does for
+    // the routes that name it in the URL
+    owners, err := roomRepository.SelectOwnersByID(r.Context(), userCred.OrgID, req.ID)
+    if err != nil {
+      if errors.Is(err, sql.ErrNoRows) {
+        response.RespondError(w, response.NotFoundError("Room not found"))
+        return
+      }
+
+      response.RespondError(w, response.InternalServerError())
+      return
+    }
+    if !middleware.RoomStaff(permissions, userCred, owners) {
+      response.RespondError(w, response.ForbiddenError("You do not have access to this room"))
+      return
+    }
+
     status, ok := repository.RoomStatusMapper("WAITING ANSWER")
     if !ok {
       response.RespondError(w, response.InternalServerError())
       return
     }
 
-    interviewee, err := userRepository.SelectIDByEmail(r.Context(), req.IntervieweeEmail)
+    interviewee, err := userRepository.SelectIDByEmailInOrg(r.Context(), userCred.OrgID, req.IntervieweeEmail)
     if err != nil {
       if errors.Is(err, sql.ErrNoRows) {
         response.RespondError(w, response.NotFoundError("User not found"))
@@ -44,6 +70,33 @@ func UpdateQuestionsAndCompetenciesRoom(roomRepository repository.RoomRepository
       return
     }
 
+    room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, req.ID)
+    if err != nil {
+      if errors.Is(err, sql.ErrNoRows) {
+        response.RespondError(w, response.NotFoundError("Room not found"))
+        return
+      }
+
+      response.RespondError(w, response.InternalServerError())
+      return
+    }
+
+    questions := orderQuestions(req.QuestionsID, room.ShuffleQuestions, room.ID, interviewee.ID)
+    if err := roomRepository.UpdateQuestionsAndCompetenciesRoom(r.Context(), userCred.OrgID, req.ID, questions, req.CompetenciesID, status, userCred.ID); err != nil {
+      fmt.Println(err)
+      if errors.Is(err, sql.ErrNoRows) {
+        response.RespondError(w, response.NotFoundError("Question or competency not found"))
+        return
+      }
+      if errors.Is(err, repository.ErrInvalidTransition) {
+        response.RespondError(w, response.ConflictError("Room has already been started"))
+        return
+      }
+
+      response.RespondError(w, response.InternalServerError())
+      return
+    }
+
     go func(ctx context.Context, interviewee repository.User) {
       auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
 
@@ -109,12 +162,6 @@ func UpdateQuestionsAndCompetenciesRoom(roomRepository repository.RoomRepository
       }
     }(context.Background(), *interviewee)
 
-    if err := roomRepository.UpdateQuestionsAndCompetenciesRoom(r.Context(), req.ID, req.QuestionsID, req.CompetenciesID, status); err != nil {
-      fmt.Println(err)
-      response.RespondError(w, response.InternalServerError())
-      return
-    }
-
     response.RespondOK(w)
   }
 }
@@ -2,22 +2,33 @@ package room
 
 import (
 	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/config"
 	"interview/summarization/repository"
 	"net/http"
 	"fmt"
 	"encoding/json"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 type UpdateQuestionCondReq struct {
+	// ignored, the start of an answer is stamped by the server
 	StartAnswer			string 		`json:"start_answer,omitempty"`
 	CurrQuestion		int				`json:"current_question"`
 	IsStarted				bool			`json:"is_started"`
 }
 
-func UpdateQuestionCond(roomRepository repository.RoomRepository) http.HandlerFunc {
+func UpdateQuestionCond(roomRepository repository.RoomRepository, cfg config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
 		roomId := chi.URLParam(r, "roomId")
 		questionId := chi.URLParam(r, "questionId")
 
@@ -26,8 +37,34 @@ func UpdateQuestionCond(roomRepository repository.RoomRepository) http.HandlerFu
 			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
 			return
 		}
-	
-		if err := roomRepository.UpdateQuestionByRoomID(r.Context(), roomId, questionId, req.StartAnswer); err != nil {
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := checkRoomWindow(room, cfg, time.Now().UTC()); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		if err := roomRepository.UpdateStatus(r.Context(), roomId, repository.InProgress, userCred.ID); err != nil {
+			if errors.Is(err, repository.ErrInvalidTransition) {
+				response.RespondError(w, response.ConflictError("Room is closed"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := roomRepository.UpdateQuestionByRoomID(r.Context(), roomId, questionId, time.Now().UTC()); err != nil {
 			fmt.Println(err)
 			if err == sql.ErrNoRows {
 				response.RespondError(w, response.NotFoundError("Question not found"))
@@ -0,0 +1,217 @@
+package room
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"interview/summarization/storage"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const defaultUploadMaxSize = 200 // megabytes
+
+// uploadTypes maps the accepted answer recordings to their file extension.
+var uploadTypes = map[string]string{
+	"audio/webm":      ".webm",
+	"audio/ogg":       ".ogg",
+	"audio/wav":       ".wav",
+	"audio/wave":      ".wav",
+	"audio/x-wav":     ".wav",
+	"audio/mpeg":      ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/x-m4a":     ".m4a",
+	"video/webm":      ".webm",
+	"video/ogg":       ".ogv",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+}
+
+var errFileTooLarge = errors.New("file too large")
+
+// sizeLimiter fails the read once more than max bytes went through, unlike
+// io.LimitReader which silently truncates the file.
+type sizeLimiter struct {
+	r   io.Reader
+	n   int64
+	max int64
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, errFileTooLarge
+	}
+
+	return n, err
+}
+
+// isMedia checks the sniffed content of the file, audio containers the
+// sniffer doesn't know are reported as octet-stream.
+func isMedia(sniffed string) bool {
+	return strings.HasPrefix(sniffed, "audio/") ||
+		strings.HasPrefix(sniffed, "video/") ||
+		sniffed == "application/ogg" ||
+		sniffed == "application/octet-stream"
+}
+
+func Upload(
+	roomRepository repository.RoomRepository,
+	attemptRepository repository.AttemptRepository,
+	jobRepository repository.JobRepository,
+	files storage.Storage,
+	cfg config.Config,
+) http.HandlerFunc {
+	maxSize := cfg.UploadMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultUploadMaxSize
+	}
+	maxSize <<= 20
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roomId := chi.URLParam(r, "roomId")
+		questionId := chi.URLParam(r, "questionId")
+
+		room, err := roomRepository.SelectOneRoomByID(r.Context(), userCred.OrgID, roomId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Room not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		question, err := roomRepository.GetOneQuestionByRoomID(r.Context(), roomId, questionId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("Question not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		submittedAt := time.Now().UTC()
+		if err := checkRoomWindow(room, cfg, submittedAt); err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		isLate, err := checkAnswerWindow(room, question, cfg, submittedAt)
+		if err != nil {
+			response.RespondError(w, windowError(err))
+			return
+		}
+
+		// leave some room for the multipart headers around the file
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+		reader, err := r.MultipartReader()
+		if err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		var part io.ReadCloser
+		var contentType string
+		for {
+			p, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+				return
+			}
+			if p.FormName() == "file" {
+				part = p
+				contentType, _, _ = mime.ParseMediaType(p.Header.Get("Content-Type"))
+				break
+			}
+			p.Close()
+		}
+		if part == nil {
+			response.RespondError(w, response.BadRequestError("File is required"))
+			return
+		}
+		defer part.Close()
+
+		ext, ok := uploadTypes[contentType]
+		if !ok {
+			response.RespondError(w, response.BadRequestError("File must be an audio or video recording"))
+			return
+		}
+
+		body := bufio.NewReaderSize(part, 512)
+		head, err := body.Peek(512)
+		if err != nil && err != io.EOF {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+		if len(head) == 0 {
+			response.RespondError(w, response.BadRequestError("File is empty"))
+			return
+		}
+		if !isMedia(http.DetectContentType(head)) {
+			response.RespondError(w, response.BadRequestError("File must be an audio or video recording"))
+			return
+		}
+
+		key := path.Join("answers", roomId, questionId, uuid.NewString()+ext)
+		err = files.Save(r.Context(), key, &sizeLimiter{r: body, max: maxSize})
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.Is(err, errFileTooLarge) || errors.As(err, &maxBytesErr) {
+				response.RespondError(w, response.BadRequestError(fmt.Sprintf("File must not exceed %d MB", maxSize>>20)))
+				return
+			}
+
+			fmt.Println(err)
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		link := fmt.Sprintf("%s/files/%s", strings.TrimSuffix(cfg.APIHost, "/"), key)
+		attempt := &repository.AnswerAttempt{
+			ID:          uuid.NewString(),
+			RoomID:      roomId,
+			QuestionID:  questionId,
+			FileLink:    link,
+			SubmittedAt: submittedAt,
+			IsLate:      isLate,
+		}
+		if err := attemptRepository.Submit(r.Context(), attempt, room.MaxAttempts, room.AttemptRule); err != nil {
+			files.Delete(r.Context(), key)
+			response.RespondError(w, attemptError(err))
+			return
+		}
+
+		if err := enqueueTranscription(r.Context(), roomRepository, jobRepository, cfg, room, attempt); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
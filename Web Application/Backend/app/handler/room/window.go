@@ -0,0 +1,81 @@
+package room
+
+import (
+	"errors"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"time"
+)
+
+var (
+	errRoomNotOpen      = errors.New("room is not open yet")
+	errRoomClosed       = errors.New("room is closed")
+	errAnswerNotStarted = errors.New("answer has not been started")
+	errAnswerClosed     = errors.New("answer window has closed")
+)
+
+// checkRoomWindow only lets candidates in between the start and the end of
+// the room, an answer sent right at the end still gets the grace period.
+func checkRoomWindow(room *repository.Room, cfg config.Config, now time.Time) error {
+	if !room.Status.IsOpen() {
+		return errRoomClosed
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, room.Start)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(time.RFC3339Nano, room.End)
+	if err != nil {
+		return err
+	}
+
+	if now.Before(start) {
+		return errRoomNotOpen
+	}
+	if now.After(end.Add(cfg.AnswerGrace())) {
+		return errRoomClosed
+	}
+
+	return nil
+}
+
+// checkAnswerWindow accepts an answer until the preparation and the duration
+// of the question have passed since it was started. Answers that only make it
+// within the grace period are accepted but reported as late.
+func checkAnswerWindow(
+	room *repository.Room,
+	question *repository.QuestionInRoom,
+	cfg config.Config,
+	now time.Time,
+) (bool, error) {
+	if !question.StartAnswer.Valid {
+		return false, errAnswerNotStarted
+	}
+
+	deadline := question.StartAnswer.Time.
+		Add(time.Duration(room.PrepationTime) * time.Second).
+		Add(time.Duration(question.DurationLimit) * time.Minute)
+
+	if now.After(deadline.Add(cfg.AnswerGrace())) {
+		return false, errAnswerClosed
+	}
+
+	return now.After(deadline), nil
+}
+
+func windowError(err error) response.Error {
+	switch {
+	case errors.Is(err, errRoomNotOpen):
+		return response.ConflictError("Room is not open yet")
+	case errors.Is(err, errRoomClosed):
+		return response.ConflictError("Room is closed")
+	case errors.Is(err, errAnswerNotStarted):
+		return response.ConflictError("Question has not been started")
+	case errors.Is(err, errAnswerClosed):
+		return response.ConflictError("Time to answer the question is over")
+	}
+
+	return response.InternalServerError()
+}
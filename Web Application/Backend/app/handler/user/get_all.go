@@ -0,0 +1,85 @@
+package user
+
+import (
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+	"time"
+)
+
+type User struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Phone     string `json:"phone"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+type GetAllResponse struct {
+	Data []User `json:"data"`
+}
+
+func toUser(user *repository.User) User {
+	return User{
+		ID:        user.ID,
+		Name:      user.Name,
+		Phone:     user.Phone,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		Status:    string(user.Status),
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// GetAll lists the users, optionally narrowed down by the role, status and
+// search (name or email) query parameters.
+func GetAll(userRepository repository.UserRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		query := r.URL.Query()
+		filter := repository.UserFilter{
+			Search: query.Get("search"),
+		}
+
+		if role := query.Get("role"); role != "" {
+			userRole, ok := repository.UserRoleMapper(role)
+			if !ok {
+				response.RespondError(w, response.BadRequestError("Invalid Role"))
+				return
+			}
+			filter.Role = userRole
+		}
+
+		if status := query.Get("status"); status != "" {
+			userStatus, ok := repository.UserStatusMapper(status)
+			if !ok {
+				response.RespondError(w, response.BadRequestError("Invalid Status"))
+				return
+			}
+			filter.Status = userStatus
+		}
+
+		users, err := userRepository.SelectAllByFilter(r.Context(), userCred.OrgID, filter)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		resp := GetAllResponse{
+			Data: []User{},
+		}
+		for _, user := range users {
+			resp.Data = append(resp.Data, toUser(user))
+		}
+
+		response.Respond(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,204 @@
+package user
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/handler/auth"
+	"interview/summarization/app/response"
+	"interview/summarization/config"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultInviteExpire = 72 // hours
+
+type InviteRequest struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	// OrgID invites the user into another organization, it needs the
+	// organization:manage permission.
+	OrgID string `json:"org_id,omitempty"`
+}
+
+// Invite creates an account with the given role and mails the user a link to
+// set their password. Inviting an email that hasn't accepted its invitation
+// yet sends a new link.
+func Invite(
+	userRepository repository.UserRepository,
+	passwordResetRepository repository.PasswordResetRepository,
+	organizationRepository repository.OrganizationRepository,
+	permissions *permission.Table,
+	cfg config.Config,
+) http.HandlerFunc {
+	expire := cfg.InviteExpire
+	if expire <= 0 {
+		expire = defaultInviteExpire
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := InviteRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		if req.Name == "" || req.Email == "" {
+			response.RespondError(w, response.BadRequestError("Name and email are required"))
+			return
+		}
+
+		role, ok := repository.UserRoleMapper(req.Role)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Role"))
+			return
+		}
+
+		orgId := userCred.OrgID
+		if req.OrgID != "" && req.OrgID != orgId {
+			if !permissions.Has(userCred.Role, permission.OrganizationManage) {
+				response.RespondError(w, response.ForbiddenError("Forbidden"))
+				return
+			}
+			if _, err := uuid.Parse(req.OrgID); err != nil {
+				response.RespondError(w, response.BadRequestError("Invalid Organization"))
+				return
+			}
+			if _, err := organizationRepository.SelectOneByID(r.Context(), req.OrgID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.RespondError(w, response.NotFoundError("Organization not found"))
+					return
+				}
+
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+			orgId = req.OrgID
+		}
+
+		user, err := userRepository.SelectIDPasswordRoleByEmail(r.Context(), req.Email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err == nil {
+			if user.Status != repository.Invited || user.OrgID != orgId {
+				response.RespondError(w, response.ConflictError("Email already registered"))
+				return
+			}
+			role = user.Role
+		} else {
+			// the password is never told to anyone, the user sets their own
+			secret, _, err := auth.NewResetToken()
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+			hashedPass, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+			if err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			user = &repository.User{
+				ID:       uuid.NewString(),
+				OrgID:    orgId,
+				Name:     req.Name,
+				Phone:    req.Phone,
+				Email:    req.Email,
+				Password: string(hashedPass),
+				Role:     role,
+				Status:   repository.Invited,
+			}
+			if err := userRepository.Insert(r.Context(), user); err != nil {
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+		}
+
+		token, tokenHash, err := auth.NewResetToken()
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		err = passwordResetRepository.Insert(r.Context(), &repository.PasswordReset{
+			ID:        uuid.NewString(),
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			ExpiresAt: time.Now().UTC().Add(time.Duration(expire) * time.Hour),
+		})
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		go func(name, receiver string, role repository.UserRole, token string) {
+			auth := smtp.PlainAuth(cfg.SenderIdentity, cfg.SenderEmail, cfg.SenderPassword, cfg.AddressHost)
+
+			urlinvite := fmt.Sprintf("http://%s:%s/auth/reset-password?token=%s", cfg.FEHost, cfg.FEPort, url.QueryEscape(token))
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				fmt.Println("Error getting current working directory:", err)
+				return
+			}
+			tmplPath := filepath.Join(cwd, "/email_templates/invite_template.html")
+
+			tmpl, err := template.ParseFiles(tmplPath)
+			if err != nil {
+				fmt.Println("Error parsing template:", err)
+				return
+			}
+
+			data := struct {
+				Name      string
+				Role      string
+				InviteURL template.HTML
+				ExpiresIn int
+			}{
+				Name:      name,
+				Role:      string(role),
+				InviteURL: template.HTML(urlinvite),
+				ExpiresIn: expire,
+			}
+
+			var renderedContent bytes.Buffer
+			err = tmpl.Execute(&renderedContent, data)
+			if err != nil {
+				fmt.Println("Error executing template:", err)
+				return
+			}
+
+			content := "Subject: Invitation Hiremif\nMIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + renderedContent.String()
+			err = smtp.SendMail(fmt.Sprintf("%s:%d", cfg.AddressHost, cfg.AddressPort), auth, cfg.SenderEmail, []string{receiver}, []byte(content))
+			if err != nil {
+				fmt.Println("Error sending email:", err)
+			}
+		}(req.Name, req.Email, role, token)
+
+		response.RespondOK(w)
+	}
+}
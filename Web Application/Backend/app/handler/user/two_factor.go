@@ -0,0 +1,114 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TwoFactorRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+type TwoFactorRolesResponse struct {
+	Roles []repository.UserRole `json:"roles"`
+}
+
+func GetTwoFactorRoles(twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roles, err := twoFactorRepository.SelectRequiredRoles(r.Context(), userCred.OrgID)
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.Respond(w, http.StatusOK, TwoFactorRolesResponse{
+			Roles: roles,
+		})
+	}
+}
+
+// UpdateTwoFactorRoles sets the roles of the organization that have to sign
+// in with a second factor. Users of those roles without one set it up on
+// their next login, their current sessions are left alone.
+func UpdateTwoFactorRoles(twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		req := TwoFactorRolesRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		roles := []string{}
+		for _, name := range req.Roles {
+			role, ok := repository.UserRoleMapper(name)
+			if !ok {
+				response.RespondError(w, response.BadRequestError("Invalid Role"))
+				return
+			}
+
+			roles = append(roles, string(role))
+		}
+
+		if err := twoFactorRepository.UpdateRequiredRoles(r.Context(), userCred.OrgID, roles); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
+
+// ResetTwoFactor turns off two-factor authentication of a user who lost
+// their authenticator and recovery codes, they set it up again on their
+// next login if their role requires it.
+func ResetTwoFactor(userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if id == userCred.ID {
+			response.RespondError(w, response.ForbiddenError("You cannot reset your own two-factor authentication"))
+			return
+		}
+
+		if _, err := userRepository.SelectOneByID(r.Context(), userCred.OrgID, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := twoFactorRepository.Delete(r.Context(), id); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/lockout"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Unlock lets a user locked out by failed logins sign in again right away.
+func Unlock(userRepository repository.UserRepository, guard *lockout.Guard) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		user, err := userRepository.SelectOneByID(r.Context(), userCred.OrgID, chi.URLParam(r, "id"))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		if err := guard.Unlock(r.Context(), user.Email); err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		response.RespondOK(w)
+	}
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type UpdateRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// UpdateRole changes the role of a user, it applies to the sessions they
+// already have.
+func UpdateRole(userRepository repository.UserRepository, roles *permission.RoleCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+
+		req := UpdateRoleRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.RespondError(w, response.BadRequestError("Incorrect Payload Format"))
+			return
+		}
+
+		role, ok := repository.UserRoleMapper(req.Role)
+		if !ok {
+			response.RespondError(w, response.BadRequestError("Invalid Role"))
+			return
+		}
+
+		if id == userCred.ID {
+			response.RespondError(w, response.ForbiddenError("You cannot change your own role"))
+			return
+		}
+
+		err := userRepository.UpdateRole(r.Context(), &repository.User{
+			ID:    id,
+			OrgID: userCred.OrgID,
+			Role:  role,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		roles.Forget(id)
+
+		response.RespondOK(w)
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"interview/summarization/session"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Deactivate locks a user out, ending every session they have.
+func Deactivate(
+	userRepository repository.UserRepository,
+	sessionRepository repository.SessionRepository,
+	revocations *session.RevocationList,
+	roles *permission.RoleCache,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if id == userCred.ID {
+			response.RespondError(w, response.ForbiddenError("You cannot deactivate yourself"))
+			return
+		}
+
+		if _, err := userRepository.SelectOneByID(r.Context(), userCred.OrgID, id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		err := userRepository.UpdateStatus(r.Context(), &repository.User{
+			ID:     id,
+			Status: repository.Deactivated,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		revoked, err := sessionRepository.RevokeAllByUserID(r.Context(), id, "")
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		revocations.Add(revoked...)
+		roles.Forget(id)
+
+		response.RespondOK(w)
+	}
+}
+
+func Activate(userRepository repository.UserRepository, roles *permission.RoleCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userCred, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+		if !ok {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+
+		user, err := userRepository.SelectOneByID(r.Context(), userCred.OrgID, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				response.RespondError(w, response.NotFoundError("User not found"))
+				return
+			}
+
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		if user.Status != repository.Deactivated {
+			response.RespondError(w, response.ConflictError("User is not deactivated"))
+			return
+		}
+
+		err = userRepository.UpdateStatus(r.Context(), &repository.User{
+			ID:     id,
+			Status: repository.Veryfied,
+		})
+		if err != nil {
+			response.RespondError(w, response.InternalServerError())
+			return
+		}
+		roles.Forget(id)
+
+		response.RespondOK(w)
+	}
+}
@@ -2,15 +2,19 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
+	"interview/summarization/permission"
 	"interview/summarization/repository"
+	"interview/summarization/session"
 	"interview/summarization/token"
 	"net/http"
 	"strings"
 )
 
-func Auth(jwt token.JWT) func(http.Handler) http.Handler {
+func Auth(jwt token.JWT, revocations *session.RevocationList, roles *permission.RoleCache) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := strings.Split(r.Header.Get("Authorization"), "Bearer ")
@@ -20,14 +24,37 @@ func Auth(jwt token.JWT) func(http.Handler) http.Handler {
 			}
 
 			claim, err := jwt.GetClaims(authHeader[1])
+			if err != nil || claim.Type != token.AccessToken {
+				response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+				return
+			}
+
+			if claim.SessionID == "" || revocations.IsRevoked(r.Context(), claim.SessionID) {
+				response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+				return
+			}
+
+			// the role in the token may be outdated
+			user, err := roles.Lookup(r.Context(), claim.UserID)
 			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+					return
+				}
+
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+			if user.Status == repository.Deactivated {
 				response.RespondError(w, response.UnauthorizedError("Unauthorized"))
 				return
 			}
 
 			ctx := context.WithValue(r.Context(), handler.UserContextKey, handler.UserCtx{
-				ID:   claim.UserID,
-				Role: repository.UserRole(claim.Role),
+				ID:        claim.UserID,
+				OrgID:     user.OrgID,
+				Role:      user.Role,
+				SessionID: claim.SessionID,
 			})
 			next.ServeHTTP(w, r.WithContext(ctx))
 			return
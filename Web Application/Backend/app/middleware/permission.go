@@ -3,11 +3,13 @@ package middleware
 import (
 	"interview/summarization/app/handler"
 	"interview/summarization/app/response"
-	"interview/summarization/repository"
+	"interview/summarization/permission"
 	"net/http"
 )
 
-func RBAC(roles ...repository.UserRole) func(http.Handler) http.Handler {
+// RequirePermission only lets through users whose role is granted the
+// permission.
+func RequirePermission(table *permission.Table, p permission.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			usrCtx, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
@@ -16,15 +18,12 @@ func RBAC(roles ...repository.UserRole) func(http.Handler) http.Handler {
 				return
 			}
 
-			for _, role := range roles {
-				if role == usrCtx.Role {
-					next.ServeHTTP(w, r)
-					return
-				}
+			if !table.Has(usrCtx.Role, p) {
+				response.RespondError(w, response.ForbiddenError("Forbidden"))
+				return
 			}
 
-			response.RespondError(w, response.UnauthorizedError("Unauthorized"))
-			return
+			next.ServeHTTP(w, r)
 		})
 	}
 }
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"interview/summarization/app/handler"
+	"interview/summarization/app/response"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RoomPolicy decides whether the user may act on a room given who the room
+// belongs to. Users granted permission.RoomOversee see every room of their
+// organization, the others only their own.
+type RoomPolicy func(*permission.Table, handler.UserCtx, *repository.RoomOwners) bool
+
+// RoomParticipant lets in the interviewee and the interviewer of the room.
+func RoomParticipant(table *permission.Table, user handler.UserCtx, owners *repository.RoomOwners) bool {
+	return RoomCandidate(table, user, owners) || RoomStaff(table, user, owners)
+}
+
+// RoomCandidate only lets in the interviewee of the room, for the actions
+// taken while answering it.
+func RoomCandidate(_ *permission.Table, user handler.UserCtx, owners *repository.RoomOwners) bool {
+	return user.Role == repository.Interviewee &&
+		owners.IntervieweeID.Valid && owners.IntervieweeID.String == user.ID
+}
+
+// RoomStaff lets in the interviewer of the room and the users overseeing
+// every room. What they may do there is up to the permission of the route.
+func RoomStaff(table *permission.Table, user handler.UserCtx, owners *repository.RoomOwners) bool {
+	return table.Has(user.Role, permission.RoomOversee) || owners.InterviewerID == user.ID
+}
+
+// RoomGroupParticipant lets in the interviewee of the room group, the
+// interviewers of its rooms and the users overseeing every room, the same
+// users who get it listed.
+func RoomGroupParticipant(table *permission.Table, user handler.UserCtx, owners *repository.RoomGroupOwners) bool {
+	if table.Has(user.Role, permission.RoomOversee) {
+		return true
+	}
+	if user.Role == repository.Interviewee {
+		return owners.IntervieweeID.Valid && owners.IntervieweeID.String == user.ID
+	}
+
+	for _, interviewerId := range owners.InterviewerIDs {
+		if interviewerId == user.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoomAccess resolves the room named by the URL parameter and enforces the
+// policy on it. A room the user may not see is reported as forbidden, a
+// room that doesn't exist as not found.
+func RoomAccess(table *permission.Table, roomRepository repository.RoomRepository, param string, policy RoomPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usrCtx, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+			if !ok {
+				response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+				return
+			}
+
+			owners, err := roomRepository.SelectOwnersByID(r.Context(), usrCtx.OrgID, chi.URLParam(r, param))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.RespondError(w, response.NotFoundError("Room not found"))
+					return
+				}
+
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			if !policy(table, usrCtx, owners) {
+				response.RespondError(w, response.ForbiddenError("You do not have access to this room"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RoomGroupAccess resolves the room group named by the URL parameter and
+// only lets in its participants, see RoomGroupParticipant.
+func RoomGroupAccess(table *permission.Table, roomRepository repository.RoomRepository, param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			usrCtx, ok := r.Context().Value(handler.UserContextKey).(handler.UserCtx)
+			if !ok {
+				response.RespondError(w, response.UnauthorizedError("Unauthorized"))
+				return
+			}
+
+			owners, err := roomRepository.SelectGroupOwnersByID(r.Context(), usrCtx.OrgID, chi.URLParam(r, param))
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.RespondError(w, response.NotFoundError("Room group not found"))
+					return
+				}
+
+				response.RespondError(w, response.InternalServerError())
+				return
+			}
+
+			if !RoomGroupParticipant(table, usrCtx, owners) {
+				response.RespondError(w, response.ForbiddenError("You do not have access to this room group"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
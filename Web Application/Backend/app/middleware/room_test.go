@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"interview/summarization/app/handler"
+	"interview/summarization/config"
+	"interview/summarization/permission"
+	"interview/summarization/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	orgID         = "org-1"
+	roomID        = "room-1"
+	roomGroupID   = "room-group-1"
+	interviewerID = "interviewer-1"
+	intervieweeID = "interviewee-1"
+)
+
+var owners = &repository.RoomOwners{
+	RoomID:        roomID,
+	InterviewerID: interviewerID,
+	IntervieweeID: sql.NullString{String: intervieweeID, Valid: true},
+}
+
+var groupOwners = &repository.RoomGroupOwners{
+	RoomGroupID:    roomGroupID,
+	IntervieweeID:  sql.NullString{String: intervieweeID, Valid: true},
+	InterviewerIDs: []string{interviewerID},
+}
+
+// ownersRepository only knows the owners of one room and one room group of
+// one organization.
+type ownersRepository struct {
+	repository.RoomRepository
+}
+
+func (ownersRepository) SelectOwnersByID(_ context.Context, org, room string) (*repository.RoomOwners, error) {
+	if org != orgID || room != roomID {
+		return nil, sql.ErrNoRows
+	}
+
+	return owners, nil
+}
+
+func (ownersRepository) SelectGroupOwnersByID(_ context.Context, org, roomGroup string) (*repository.RoomGroupOwners, error) {
+	if org != orgID || roomGroup != roomGroupID {
+		return nil, sql.ErrNoRows
+	}
+
+	return groupOwners, nil
+}
+
+func newTable(t *testing.T, rolePermissions string) *permission.Table {
+	t.Helper()
+
+	table, err := permission.NewTable(config.Config{RolePermissions: rolePermissions})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return table
+}
+
+func TestRoomPolicies(t *testing.T) {
+	table := newTable(t, "")
+	users := map[string]handler.UserCtx{
+		"owner":           {ID: interviewerID, OrgID: orgID, Role: repository.Interviewer},
+		"non-owner":       {ID: "interviewer-2", OrgID: orgID, Role: repository.Interviewer},
+		"candidate":       {ID: intervieweeID, OrgID: orgID, Role: repository.Interviewee},
+		"other candidate": {ID: "interviewee-2", OrgID: orgID, Role: repository.Interviewee},
+		"hrd":             {ID: "hrd-1", OrgID: orgID, Role: repository.Hrd},
+	}
+
+	tests := []struct {
+		name   string
+		policy RoomPolicy
+		allow  map[string]bool
+	}{
+		{
+			name:   "participant",
+			policy: RoomParticipant,
+			allow:  map[string]bool{"owner": true, "non-owner": false, "candidate": true, "other candidate": false, "hrd": true},
+		},
+		{
+			name:   "candidate",
+			policy: RoomCandidate,
+			allow:  map[string]bool{"owner": false, "non-owner": false, "candidate": true, "other candidate": false, "hrd": false},
+		},
+		{
+			name:   "staff",
+			policy: RoomStaff,
+			allow:  map[string]bool{"owner": true, "non-owner": false, "candidate": false, "other candidate": false, "hrd": true},
+		},
+	}
+
+	for _, tt := range tests {
+		for user, want := range tt.allow {
+			t.Run(tt.name+"/"+user, func(t *testing.T) {
+				if got := tt.policy(table, users[user], owners); got != want {
+					t.Errorf("got %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRoomCandidateWithoutInterviewee(t *testing.T) {
+	// a room created outside of a room group has no interviewee
+	solo := &repository.RoomOwners{RoomID: roomID, InterviewerID: interviewerID}
+	user := handler.UserCtx{ID: "", OrgID: orgID, Role: repository.Interviewee}
+
+	if RoomCandidate(newTable(t, ""), user, solo) {
+		t.Error("an interviewee got into a room without one")
+	}
+}
+
+func TestRoomAccess(t *testing.T) {
+	table := newTable(t, "")
+	tests := []struct {
+		name   string
+		user   *handler.UserCtx
+		room   string
+		policy RoomPolicy
+		want   int
+	}{
+		{"owner", &handler.UserCtx{ID: interviewerID, OrgID: orgID, Role: repository.Interviewer}, roomID, RoomStaff, http.StatusOK},
+		{"non-owner", &handler.UserCtx{ID: "interviewer-2", OrgID: orgID, Role: repository.Interviewer}, roomID, RoomStaff, http.StatusForbidden},
+		{"candidate", &handler.UserCtx{ID: intervieweeID, OrgID: orgID, Role: repository.Interviewee}, roomID, RoomCandidate, http.StatusOK},
+		{"candidate on staff route", &handler.UserCtx{ID: intervieweeID, OrgID: orgID, Role: repository.Interviewee}, roomID, RoomStaff, http.StatusForbidden},
+		{"hrd", &handler.UserCtx{ID: "hrd-1", OrgID: orgID, Role: repository.Hrd}, roomID, RoomStaff, http.StatusOK},
+		{"hrd of another organization", &handler.UserCtx{ID: "hrd-2", OrgID: "org-2", Role: repository.Hrd}, roomID, RoomStaff, http.StatusNotFound},
+		{"missing room", &handler.UserCtx{ID: "hrd-1", OrgID: orgID, Role: repository.Hrd}, "room-2", RoomStaff, http.StatusNotFound},
+		{"no user", nil, roomID, RoomParticipant, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.With(RoomAccess(table, ownersRepository{}, "id", tt.policy)).Get("/room/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/room/"+tt.room, nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), handler.UserContextKey, *tt.user))
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomStaffFollowsPermission(t *testing.T) {
+	// the organization lets interviewers oversee every room and takes it
+	// away from HRD
+	table := newTable(t, `{"INTERVIEWER":["room:review","room:oversee"],"HRD":["room:review"]}`)
+
+	nonOwner := handler.UserCtx{ID: "interviewer-2", OrgID: orgID, Role: repository.Interviewer}
+	if !RoomStaff(table, nonOwner, owners) {
+		t.Error("an interviewer granted room:oversee was kept out of a room")
+	}
+
+	hrd := handler.UserCtx{ID: "hrd-1", OrgID: orgID, Role: repository.Hrd}
+	if RoomStaff(table, hrd, owners) {
+		t.Error("HRD without room:oversee got into a room they don't interview")
+	}
+}
+
+func TestRoomGroupAccess(t *testing.T) {
+	table := newTable(t, "")
+
+	tests := []struct {
+		name  string
+		user  *handler.UserCtx
+		group string
+		want  int
+	}{
+		{"interviewer of a room", &handler.UserCtx{ID: interviewerID, OrgID: orgID, Role: repository.Interviewer}, roomGroupID, http.StatusOK},
+		{"other interviewer", &handler.UserCtx{ID: "interviewer-2", OrgID: orgID, Role: repository.Interviewer}, roomGroupID, http.StatusForbidden},
+		{"candidate", &handler.UserCtx{ID: intervieweeID, OrgID: orgID, Role: repository.Interviewee}, roomGroupID, http.StatusOK},
+		{"other candidate", &handler.UserCtx{ID: "interviewee-2", OrgID: orgID, Role: repository.Interviewee}, roomGroupID, http.StatusForbidden},
+		{"hrd", &handler.UserCtx{ID: "hrd-1", OrgID: orgID, Role: repository.Hrd}, roomGroupID, http.StatusOK},
+		{"hrd of another organization", &handler.UserCtx{ID: "hrd-2", OrgID: "org-2", Role: repository.Hrd}, roomGroupID, http.StatusNotFound},
+		{"missing room group", &handler.UserCtx{ID: "hrd-1", OrgID: orgID, Role: repository.Hrd}, "room-group-2", http.StatusNotFound},
+		{"no user", nil, roomGroupID, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.With(RoomGroupAccess(table, ownersRepository{}, "id")).Get("/room/group/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/room/group/"+tt.group, nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), handler.UserContextKey, *tt.user))
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -21,6 +21,13 @@ func UnauthorizedError(message string) Error {
 	}
 }
 
+func ForbiddenError(message string) Error {
+	return Error{
+		StatusCode: http.StatusForbidden,
+		Message:    message,
+	}
+}
+
 func NotFoundError(message string) Error {
 	return Error{
 		StatusCode: http.StatusNotFound,
@@ -28,6 +35,20 @@ func NotFoundError(message string) Error {
 	}
 }
 
+func ConflictError(message string) Error {
+	return Error{
+		StatusCode: http.StatusConflict,
+		Message:    message,
+	}
+}
+
+func TooManyRequestsError(message string) Error {
+	return Error{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    message,
+	}
+}
+
 func InternalServerError() Error {
 	return Error{
 		StatusCode: http.StatusInternalServerError,
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+const defaultAnswerGracePeriod = 15 // seconds
+
+// defaultOrgID is the organization created by the schema for sign-ups that
+// don't name one.
+const defaultOrgID = "00000000-0000-0000-0000-000000000001"
+
 type Config struct {
 	DBHost     string `mapstructure:"DB_HOST"`
 	DBPort     string `mapstructure:"DB_PORT"`
@@ -17,18 +25,67 @@ type Config struct {
 	SummarizationHostEN string `mapstructure:"SUMMARIZATION_HOST_EN"`
 	SpeechToTextHostID 	string `mapstructure:"SPEECH_TO_TEXT_HOST_ID"`
 	SummarizationHostID string `mapstructure:"SUMMARIZATION_HOST_ID"`
+	Languages            string `mapstructure:"LANGUAGES"`
+	SpeechToTextTimeout  int    `mapstructure:"SPEECH_TO_TEXT_TIMEOUT"`
+	SummarizationTimeout int    `mapstructure:"SUMMARIZATION_TIMEOUT"`
 	FEHost							string `mapstructure:"FE_HOST"`
 	FEPort							string `mapstructure:"FE_PORT"`
 
 	TokenSecret        string `mapstructure:"TOKEN_SECRET"`
 	AccessTokenExpire  int    `mapstructure:"ACCESS_TOKEN_EXPIRE"`
 	RefreshTokenExpire int    `mapstructure:"REFRESH_TOKEN_EXPIRE"`
+	RevocationRefreshInterval int `mapstructure:"REVOCATION_REFRESH_INTERVAL"`
+	PasswordResetExpire       int `mapstructure:"PASSWORD_RESET_EXPIRE"`
+	VerificationTokenExpire   int `mapstructure:"VERIFICATION_TOKEN_EXPIRE"`
+	VerificationResendCooldown int `mapstructure:"VERIFICATION_RESEND_COOLDOWN"`
+	InviteExpire              int `mapstructure:"INVITE_EXPIRE"`
+	DefaultOrgID              string `mapstructure:"DEFAULT_ORG_ID"`
+	RolePermissions           string `mapstructure:"ROLE_PERMISSIONS"`
+	RoleRefreshInterval       int `mapstructure:"ROLE_REFRESH_INTERVAL"`
+	LoginMaxFailures          int `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures        int `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginLockout              int `mapstructure:"LOGIN_LOCKOUT"`
+	LoginLockoutMax           int `mapstructure:"LOGIN_LOCKOUT_MAX"`
+	TwoFactorChallengeExpire  int `mapstructure:"TWO_FACTOR_CHALLENGE_EXPIRE"`
+	TwoFactorEnrollmentExpire int `mapstructure:"TWO_FACTOR_ENROLLMENT_EXPIRE"`
 
 	SenderIdentity string `mapstructure:"SENDER_IDENTITY"`
 	SenderEmail    string `mapstructure:"SENDER_EMAIL"`
 	SenderPassword string `mapstructure:"SENDER_PASSWORD"`
 	AddressHost    string `mapstructure:"ADDRESS_HOST"`
 	AddressPort    int    `mapstructure:"ADDRESS_PORT"`
+
+	StorageDriver   string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalDir string `mapstructure:"STORAGE_LOCAL_DIR"`
+	UploadMaxSize   int64  `mapstructure:"UPLOAD_MAX_SIZE"`
+	SignedURLExpire int    `mapstructure:"SIGNED_URL_EXPIRE"`
+
+	AnswerGracePeriod int `mapstructure:"ANSWER_GRACE_PERIOD"`
+
+	WorkerPoolSize   int `mapstructure:"WORKER_POOL_SIZE"`
+	JobMaxAttempts   int `mapstructure:"JOB_MAX_ATTEMPTS"`
+	JobLeaseDuration int `mapstructure:"JOB_LEASE_DURATION"`
+	JobPollInterval  int `mapstructure:"JOB_POLL_INTERVAL"`
+}
+
+// AnswerGrace is how long after a time limit an answer is still accepted.
+func (c Config) AnswerGrace() time.Duration {
+	grace := c.AnswerGracePeriod
+	if grace <= 0 {
+		grace = defaultAnswerGracePeriod
+	}
+
+	return time.Duration(grace) * time.Second
+}
+
+// DefaultOrganization is the organization of the candidates who sign up
+// without a registration link.
+func (c Config) DefaultOrganization() string {
+	if c.DefaultOrgID == "" {
+		return defaultOrgID
+	}
+
+	return c.DefaultOrgID
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -0,0 +1,26 @@
+package cron_job
+
+import (
+	"context"
+	"fmt"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"time"
+)
+
+// ExpireRooms moves the rooms whose end and grace period have passed without
+// a submission to EXPIRED.
+func ExpireRooms(roomRepository repository.RoomRepository, cfg config.Config) error {
+	ctx := context.Background()
+
+	expired, err := roomRepository.ExpireRooms(ctx, time.Now().UTC().Add(-cfg.AnswerGrace()))
+	if err != nil {
+		return fmt.Errorf("failed to expire rooms: %w", err)
+	}
+
+	if expired > 0 {
+		fmt.Println("Expired rooms", expired)
+	}
+
+	return nil
+}
@@ -1,36 +1,43 @@
 package cron_job
 
 import (
-	"interview/summarization/repository"
-	"net/http"
 	"context"
 	"fmt"
+	"interview/summarization/mlclient"
+	"interview/summarization/repository"
 )
 
-func TrainModel(feedbackRepository repository.FeedbackRepository, summarizationHost string) error {
+// TrainModel retrains the model of every organization with new feedback,
+// the answers of one organization are never used to train another's.
+func TrainModel(
+	feedbackRepository repository.FeedbackRepository,
+	organizationRepository repository.OrganizationRepository,
+	scorer mlclient.CompetencyScorer,
+	language string,
+) error {
 	ctx := context.Background()
 
-	// Check if data is available
-	IsDataAvailable, err := feedbackRepository.IsDataAvailable(ctx)
+	organizations, err := organizationRepository.SelectAll(ctx)
 	if err != nil {
-			return err
-	}
-
-	if !IsDataAvailable {
-			return nil
+		return err
 	}
 
-	fmt.Println("Train data")
-	url := summarizationHost + "/train"
-	resp, err := http.Post(url, "application/json", nil)
-	if err != nil {
+	for _, org := range organizations {
+		// Check if data is available
+		IsDataAvailable, err := feedbackRepository.IsDataAvailable(ctx, org.ID, language)
+		if err != nil {
 			return err
-	}
-	defer resp.Body.Close()
+		}
+
+		if !IsDataAvailable {
+			continue
+		}
 
-	if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("failed to train model, status code: %d", resp.StatusCode)
+		fmt.Println("Train data", language, org.ID)
+		if err := scorer.Train(ctx, language, org.ID); err != nil {
+			return fmt.Errorf("failed to train model: %w", err)
+		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
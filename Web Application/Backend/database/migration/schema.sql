@@ -1,19 +1,112 @@
+CREATE TABLE IF NOT EXISTS organizations(
+  id UUID PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  deleted BOOLEAN DEFAULT false NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE,
+  deleted_at TIMESTAMP WITH TIME ZONE
+);
+
 CREATE TABLE IF NOT EXISTS users(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   name TEXT NOT NULL,
   phone TEXT NOT NULL,
   email TEXT NOT NULL UNIQUE,
   password TEXT NOT NULL,
   role TEXT NOT NULL,
   status TEXT,
+  verification_sent_at TIMESTAMP WITH TIME ZONE,
   deleted BOOLEAN DEFAULT false NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE,
-  deleted_at TIMESTAMP WITH TIME ZONE
+  deleted_at TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY(org_id) REFERENCES organizations(id)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE INDEX IF NOT EXISTS users_org_id_idx ON users(org_id);
+
+CREATE TABLE IF NOT EXISTS sessions(
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  user_agent TEXT NOT NULL,
+  ip_address TEXT NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  revoked_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions(user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  session_id UUID NOT NULL,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  revoked_at TIMESTAMP WITH TIME ZONE,
+  replaced_by UUID,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id),
+  FOREIGN KEY(session_id) REFERENCES sessions(id)
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_id_idx ON refresh_tokens(session_id);
+
+CREATE TABLE IF NOT EXISTS password_resets(
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  used_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS two_factors(
+  user_id UUID PRIMARY KEY,
+  secret TEXT NOT NULL,
+  enabled_at TIMESTAMP WITH TIME ZONE,
+  last_used_step BIGINT DEFAULT 0 NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes(
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  code_hash TEXT NOT NULL,
+  used_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes(user_id);
+
+CREATE TABLE IF NOT EXISTS two_factor_roles(
+  org_id UUID NOT NULL,
+  role TEXT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY(org_id, role),
+  FOREIGN KEY(org_id) REFERENCES organizations(id)
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts(
+  subject TEXT NOT NULL,
+  key TEXT NOT NULL,
+  failures INT DEFAULT 0 NOT NULL,
+  lockouts INT DEFAULT 0 NOT NULL,
+  locked_until TIMESTAMP WITH TIME ZONE,
+  last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY(subject, key)
 );
 
 CREATE TABLE IF NOT EXISTS room_groups(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   title TEXT NOT NULL,
   org_position TEXT NOT NULL,
   interviewee_id UUID,
@@ -21,11 +114,17 @@ CREATE TABLE IF NOT EXISTS room_groups(
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE,
   deleted_at TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY(org_id) REFERENCES organizations(id),
   FOREIGN KEY(interviewee_id) REFERENCES users(id)
 );
 
+ALTER TABLE room_groups ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE INDEX IF NOT EXISTS room_groups_org_id_idx ON room_groups(org_id);
+
 CREATE TABLE IF NOT EXISTS rooms(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   title TEXT NOT NULL,
   description TEXT,
   "start" TIMESTAMP WITH TIME ZONE NOT NULL,
@@ -37,49 +136,94 @@ CREATE TABLE IF NOT EXISTS rooms(
   note TEXT,
   language TEXT,
   preparation_time INT,
+  shuffle_questions BOOLEAN DEFAULT false NOT NULL,
+  max_attempts INT DEFAULT 1 NOT NULL,
+  attempt_rule TEXT DEFAULT 'LATEST' NOT NULL,
   interviewer_id UUID,
   room_group_id UUID,
   deleted BOOLEAN DEFAULT false NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE,
   deleted_at TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY(org_id) REFERENCES organizations(id),
   FOREIGN KEY(interviewer_id) REFERENCES users(id),
   FOREIGN KEY(room_group_id) REFERENCES room_groups(id)
 );
 
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE INDEX IF NOT EXISTS rooms_org_id_idx ON rooms(org_id);
+
 CREATE TABLE IF NOT EXISTS questions(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   question TEXT NOT NULL,
   duration_limit INT NOT NULL,
   org_position TEXT NOT NULL,
   deleted BOOLEAN DEFAULT false NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE,
-  deleted_at TIMESTAMP WITH TIME ZONE
+  deleted_at TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY(org_id) REFERENCES organizations(id)
 );
 
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE INDEX IF NOT EXISTS questions_org_id_idx ON questions(org_id);
+
 CREATE TABLE IF NOT EXISTS rooms_has_questions(
   room_id UUID,
   question_id UUID,
+  position INT DEFAULT 0 NOT NULL,
   start_answer TIMESTAMP WITH TIME ZONE,
   file_link TEXT,
   transcript TEXT,
+  submitted_at TIMESTAMP WITH TIME ZONE,
+  is_late BOOLEAN DEFAULT false NOT NULL,
+  processing_status TEXT DEFAULT 'NOT_SUBMITTED' NOT NULL,
+  processing_error TEXT,
+  queued_at TIMESTAMP WITH TIME ZONE,
+  transcribed_at TIMESTAMP WITH TIME ZONE,
+  scored_at TIMESTAMP WITH TIME ZONE,
+  failed_at TIMESTAMP WITH TIME ZONE,
+  status_updated_at TIMESTAMP WITH TIME ZONE,
   FOREIGN KEY(room_id) REFERENCES rooms(id),
   FOREIGN KEY(question_id) REFERENCES questions(id),
   PRIMARY KEY(room_id, question_id)
 );
 
+CREATE TABLE IF NOT EXISTS answer_attempts(
+  id UUID PRIMARY KEY,
+  room_id UUID NOT NULL,
+  question_id UUID NOT NULL,
+  attempt INT NOT NULL,
+  file_link TEXT NOT NULL,
+  transcript TEXT,
+  submitted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  is_late BOOLEAN DEFAULT false NOT NULL,
+  selected BOOLEAN DEFAULT false NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(room_id, question_id) REFERENCES rooms_has_questions(room_id, question_id),
+  UNIQUE(room_id, question_id, attempt)
+);
+
 CREATE TABLE IF NOT EXISTS competencies(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   competency TEXT NOT NULL,
   description TEXT NOT NULL,
   category TEXT NOT NULL,
   deleted BOOLEAN DEFAULT false NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP WITH TIME ZONE,
-  deleted_at TIMESTAMP WITH TIME ZONE
+  deleted_at TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY(org_id) REFERENCES organizations(id)
 );
 
+ALTER TABLE competencies ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE INDEX IF NOT EXISTS competencies_org_id_idx ON competencies(org_id);
+
 CREATE TABLE IF NOT EXISTS rooms_has_competencies(
   room_id UUID,
   competency_id UUID,
@@ -99,15 +243,66 @@ CREATE TABLE IF NOT EXISTS competency_levels(
   FOREIGN KEY(competency_id) REFERENCES competencies(id)
 );
 
+CREATE TABLE IF NOT EXISTS result_sets(
+  id UUID PRIMARY KEY,
+  room_id UUID NOT NULL,
+  job_id UUID,
+  model_version TEXT,
+  language TEXT,
+  transcript_hash TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(room_id) REFERENCES rooms(id)
+);
+
+CREATE INDEX IF NOT EXISTS result_sets_room_id_created_at_idx ON result_sets(room_id, created_at);
+
 CREATE TABLE IF NOT EXISTS results_competencies(
+  result_set_id UUID NOT NULL,
   room_id UUID,
   competency TEXT NOT NULL,
   level TEXT NOT NULL,
   result REAL NOT NULL,
+  FOREIGN KEY(result_set_id) REFERENCES result_sets(id),
   FOREIGN KEY(room_id) REFERENCES rooms(id),
-  PRIMARY KEY(room_id, competency, level)
+  PRIMARY KEY(result_set_id, competency, level)
 );
 
+ALTER TABLE results_competencies ADD COLUMN IF NOT EXISTS result_set_id UUID;
+
+-- Databases created before result history kept one set of scores per room
+-- keyed by the room. Each of those rooms gets a result set of its own that
+-- the scores move into, scores without a room can't be shown and are dropped.
+DELETE FROM results_competencies WHERE result_set_id IS NULL AND room_id IS NULL;
+
+INSERT INTO result_sets(id, room_id, language, created_at)
+  SELECT gen_random_uuid(), r.id, r.language, COALESCE(r.updated_at, r.created_at)
+  FROM rooms r
+  WHERE EXISTS(SELECT 1 FROM results_competencies rc WHERE rc.room_id = r.id AND rc.result_set_id IS NULL)
+    AND NOT EXISTS(SELECT 1 FROM result_sets rs WHERE rs.room_id = r.id AND rs.job_id IS NULL);
+
+UPDATE results_competencies rc SET result_set_id = rs.id
+  FROM result_sets rs
+  WHERE rc.result_set_id IS NULL AND rs.room_id = rc.room_id AND rs.job_id IS NULL;
+
+ALTER TABLE results_competencies ALTER COLUMN result_set_id SET NOT NULL;
+
+DO $$
+BEGIN
+  IF NOT EXISTS(SELECT 1 FROM pg_constraint WHERE conname = 'results_competencies_result_set_id_fkey') THEN
+    ALTER TABLE results_competencies ADD CONSTRAINT results_competencies_result_set_id_fkey
+      FOREIGN KEY(result_set_id) REFERENCES result_sets(id);
+  END IF;
+
+  IF NOT EXISTS(
+    SELECT 1 FROM pg_constraint c
+    INNER JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY(c.conkey)
+    WHERE c.conname = 'results_competencies_pkey' AND a.attname = 'result_set_id'
+  ) THEN
+    ALTER TABLE results_competencies DROP CONSTRAINT IF EXISTS results_competencies_pkey;
+    ALTER TABLE results_competencies ADD PRIMARY KEY(result_set_id, competency, level);
+  END IF;
+END $$;
+
 CREATE TABLE IF NOT EXISTS questions_labels(
   id UUID PRIMARY KEY,
   question_id UUID,
@@ -122,13 +317,90 @@ CREATE TABLE IF NOT EXISTS questions_labels(
 
 CREATE TABLE IF NOT EXISTS feedback_results(
   id UUID PRIMARY KEY,
+  org_id UUID NOT NULL,
   transcript TEXT,
   competency_id UUID,
   status TEXT,
   language TEXT,
   label_result UUID,
   label_feedback UUID,
+  FOREIGN KEY(org_id) REFERENCES organizations(id),
   FOREIGN KEY(competency_id) REFERENCES competencies(id),
   FOREIGN KEY(label_result) REFERENCES competency_levels(id),
   FOREIGN KEY(label_feedback) REFERENCES competency_levels(id)
 );
+
+ALTER TABLE feedback_results ADD COLUMN IF NOT EXISTS org_id UUID;
+
+CREATE TABLE IF NOT EXISTS room_status_events(
+  id UUID PRIMARY KEY,
+  room_id UUID NOT NULL,
+  actor_id UUID,
+  from_status TEXT,
+  to_status TEXT NOT NULL,
+  note TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY(room_id) REFERENCES rooms(id),
+  FOREIGN KEY(actor_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS room_status_events_room_id_idx ON room_status_events(room_id, created_at);
+
+CREATE TABLE IF NOT EXISTS jobs(
+  id UUID PRIMARY KEY,
+  type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  dedupe_key TEXT,
+  status TEXT NOT NULL,
+  attempts INT DEFAULT 0 NOT NULL,
+  max_attempts INT NOT NULL,
+  run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_by TEXT,
+  locked_until TIMESTAMP WITH TIME ZONE,
+  last_error TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE,
+  completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS jobs_status_run_at_idx ON jobs(status, run_at);
+
+-- A running job may have read its input before it changed, so only waiting
+-- jobs are deduplicated and a new one can queue up behind a running one.
+DROP INDEX IF EXISTS jobs_dedupe_key_active_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS jobs_dedupe_key_pending_idx ON jobs(dedupe_key)
+  WHERE status = 'PENDING';
+
+-- The default organization takes the candidates who sign up without naming
+-- one (DEFAULT_ORG_ID). Databases created before organizations existed only
+-- got a nullable org_id above, their rows are moved to it before the column
+-- becomes required. On a new database there is nothing to move.
+INSERT INTO organizations(id, name)
+  VALUES('00000000-0000-0000-0000-000000000001', 'Default Organization')
+  ON CONFLICT DO NOTHING;
+
+UPDATE users SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+UPDATE room_groups SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+UPDATE rooms SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+UPDATE questions SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+UPDATE competencies SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+UPDATE feedback_results SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+
+ALTER TABLE users ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE room_groups ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE rooms ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE questions ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE competencies ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE feedback_results ALTER COLUMN org_id SET NOT NULL;
+
+DO $$
+DECLARE
+  t TEXT;
+BEGIN
+  FOREACH t IN ARRAY ARRAY['users', 'room_groups', 'rooms', 'questions', 'competencies', 'feedback_results'] LOOP
+    IF NOT EXISTS(SELECT 1 FROM pg_constraint WHERE conname = t || '_org_id_fkey') THEN
+      EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I FOREIGN KEY(org_id) REFERENCES organizations(id)', t, t || '_org_id_fkey');
+    END IF;
+  END LOOP;
+END $$;
@@ -0,0 +1,128 @@
+package language
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview/summarization/config"
+	"strings"
+)
+
+// Language holds everything the services need to know to process answers
+// given in one language.
+type Language struct {
+	Code             string `json:"code"`
+	SpeechToTextHost string `json:"speech_to_text_host"`
+	SpeechToTextPath string `json:"speech_to_text_path"`
+	ScorerHost       string `json:"scorer_host"`
+	// ScorerVariant picks the model of the summarization host, it is served
+	// under /predict/{variant}. An empty variant uses the default /predict.
+	ScorerVariant string `json:"scorer_variant"`
+	// TrainingSchedule is the cron spec on which the model is retrained with
+	// the collected feedback. An empty schedule disables scheduled training.
+	TrainingSchedule string `json:"training_schedule"`
+	// Labeling enables the interviewer labeling flow, the model is retrained
+	// as soon as every requested feedback has been labeled.
+	Labeling bool `json:"labeling"`
+}
+
+func (l *Language) SpeechToTextURL() string {
+	return l.SpeechToTextHost + l.SpeechToTextPath
+}
+
+func (l *Language) PredictPath() string {
+	if l.ScorerVariant == "" {
+		return "/predict"
+	}
+
+	return "/predict/" + l.ScorerVariant
+}
+
+type Registry struct {
+	languages map[string]*Language
+	codes     []string
+}
+
+// NewRegistry builds the registry from the LANGUAGES config, a JSON array of
+// languages. Without it the registry falls back to the English and
+// Indonesian hosts of the older per-language config keys.
+func NewRegistry(cfg config.Config) (*Registry, error) {
+	languages := []*Language{}
+	if strings.TrimSpace(cfg.Languages) != "" {
+		if err := json.Unmarshal([]byte(cfg.Languages), &languages); err != nil {
+			return nil, fmt.Errorf("invalid LANGUAGES config: %w", err)
+		}
+	} else {
+		languages = legacyLanguages(cfg)
+	}
+
+	r := &Registry{
+		languages: make(map[string]*Language, len(languages)),
+	}
+	for _, l := range languages {
+		l.Code = strings.ToUpper(strings.TrimSpace(l.Code))
+		if l.Code == "" {
+			return nil, fmt.Errorf("invalid LANGUAGES config: language without code")
+		}
+		if _, ok := r.languages[l.Code]; ok {
+			return nil, fmt.Errorf("invalid LANGUAGES config: duplicate language %s", l.Code)
+		}
+		if l.SpeechToTextHost == "" || l.ScorerHost == "" {
+			return nil, fmt.Errorf("invalid LANGUAGES config: %s needs a speech to text and a scorer host", l.Code)
+		}
+
+		r.languages[l.Code] = l
+		r.codes = append(r.codes, l.Code)
+	}
+
+	if len(r.codes) == 0 {
+		return nil, fmt.Errorf("no language configured")
+	}
+
+	return r, nil
+}
+
+func legacyLanguages(cfg config.Config) []*Language {
+	return []*Language{
+		{
+			Code:             "ENGLISH",
+			SpeechToTextHost: cfg.SpeechToTextHostEN,
+			SpeechToTextPath: "/predict/english",
+			ScorerHost:       cfg.SummarizationHostEN,
+			Labeling:         true,
+		},
+		{
+			Code:             "INDONESIAN",
+			SpeechToTextHost: cfg.SpeechToTextHostID,
+			SpeechToTextPath: "/predict/indonesian",
+			ScorerHost:       cfg.SummarizationHostID,
+			ScorerVariant:    "laddernetwork",
+			TrainingSchedule: "0 2 1 * *",
+		},
+	}
+}
+
+func (r *Registry) Get(code string) (*Language, bool) {
+	l, ok := r.languages[code]
+	return l, ok
+}
+
+// All returns the languages in the order they were configured.
+func (r *Registry) All() []*Language {
+	languages := make([]*Language, 0, len(r.codes))
+	for _, code := range r.codes {
+		languages = append(languages, r.languages[code])
+	}
+
+	return languages
+}
+
+// Labeling returns the code of the first language using the labeling flow.
+func (r *Registry) Labeling() (string, bool) {
+	for _, code := range r.codes {
+		if r.languages[code].Labeling {
+			return code, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,103 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxFailures   = 5
+	defaultIPMaxFailures = 20
+	defaultLockout       = 5    // minutes
+	defaultLockoutMax    = 1440 // minutes
+)
+
+// Guard counts the failed logins of every email and IP address and locks
+// them out for longer each time they keep failing. Where the counters live
+// is up to the repository it is given.
+type Guard struct {
+	attemptRepository repository.LoginAttemptRepository
+	maxFailures       int
+	ipMaxFailures     int
+	lockout           time.Duration
+	lockoutMax        time.Duration
+}
+
+func NewGuard(attemptRepository repository.LoginAttemptRepository, cfg config.Config) *Guard {
+	maxFailures := cfg.LoginMaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultMaxFailures
+	}
+
+	ipMaxFailures := cfg.LoginIPMaxFailures
+	if ipMaxFailures <= 0 {
+		ipMaxFailures = defaultIPMaxFailures
+	}
+
+	lockout := cfg.LoginLockout
+	if lockout <= 0 {
+		lockout = defaultLockout
+	}
+
+	lockoutMax := cfg.LoginLockoutMax
+	if lockoutMax <= 0 {
+		lockoutMax = defaultLockoutMax
+	}
+	if lockoutMax < lockout {
+		lockoutMax = lockout
+	}
+
+	return &Guard{
+		attemptRepository: attemptRepository,
+		maxFailures:       maxFailures,
+		ipMaxFailures:     ipMaxFailures,
+		lockout:           time.Duration(lockout) * time.Minute,
+		lockoutMax:        time.Duration(lockoutMax) * time.Minute,
+	}
+}
+
+// emailKey makes the spellings of an email share one counter.
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// LockedUntil returns when the later lock of the email and the IP address
+// ends, it is not valid when neither is locked.
+func (g *Guard) LockedUntil(ctx context.Context, email, ip string) (sql.NullTime, error) {
+	emailLock, err := g.attemptRepository.SelectLockedUntil(ctx, repository.LoginEmail, emailKey(email))
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+
+	ipLock, err := g.attemptRepository.SelectLockedUntil(ctx, repository.LoginIP, ip)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+
+	if ipLock.Valid && (!emailLock.Valid || ipLock.Time.After(emailLock.Time)) {
+		return ipLock, nil
+	}
+
+	return emailLock, nil
+}
+
+// Fail counts a failed login. The end of the lock is returned when this
+// failure locked the email.
+func (g *Guard) Fail(ctx context.Context, email, ip string) (sql.NullTime, error) {
+	if _, err := g.attemptRepository.RecordFailure(ctx, repository.LoginIP, ip, g.ipMaxFailures, g.lockout, g.lockoutMax); err != nil {
+		return sql.NullTime{}, err
+	}
+
+	return g.attemptRepository.RecordFailure(ctx, repository.LoginEmail, emailKey(email), g.maxFailures, g.lockout, g.lockoutMax)
+}
+
+// Unlock forgets the failures of the email, after it signed in or was
+// unlocked by HRD. The failures of the IP address are kept, signing in to
+// one account doesn't make guessing the others any better.
+func (g *Guard) Unlock(ctx context.Context, email string) error {
+	return g.attemptRepository.Reset(ctx, repository.LoginEmail, emailKey(email))
+}
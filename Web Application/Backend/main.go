@@ -8,13 +8,24 @@ import (
 	questionhandler "interview/summarization/app/handler/question"
 	roomhandler "interview/summarization/app/handler/room"
 	feedbackhandler "interview/summarization/app/handler/feedback"
+	languagehandler "interview/summarization/app/handler/language"
+	mediahandler "interview/summarization/app/handler/media"
+	userhandler "interview/summarization/app/handler/user"
+	organizationhandler "interview/summarization/app/handler/organization"
 	"interview/summarization/app/middleware"
 	"interview/summarization/config"
 	"interview/summarization/database"
+	"interview/summarization/language"
+	"interview/summarization/lockout"
 	"interview/summarization/repository"
+	"interview/summarization/permission"
 	"interview/summarization/repository/pgsql"
+	"interview/summarization/session"
+	"interview/summarization/storage"
 	"interview/summarization/token/jwt"
 	"interview/summarization/cron_job"
+	"interview/summarization/mlclient"
+	"interview/summarization/queue"
 	"log"
 	"net/http"
 	// "time"
@@ -38,6 +49,21 @@ func main() {
 
 	jwtImpl := jwt.NewJWT(cfg)
 
+	languages, err := language.NewRegistry(cfg)
+	if err != nil {
+		log.Fatalln("language registry:", err)
+	}
+
+	permissions, err := permission.NewTable(cfg)
+	if err != nil {
+		log.Fatalln("permission table:", err)
+	}
+
+	files, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalln("storage:", err)
+	}
+
 	userRepository, err := pgsql.NewUserRepository(db)
 	if err != nil {
 		log.Fatalln("user repository:", err)
@@ -63,13 +89,82 @@ func main() {
 		log.Fatalln("feedback repository:", err)
 	}
 
+	jobRepository, err := pgsql.NewJobRepository(db)
+	if err != nil {
+		log.Fatalln("job repository:", err)
+	}
+
+	attemptRepository, err := pgsql.NewAttemptRepository(db)
+	if err != nil {
+		log.Fatalln("attempt repository:", err)
+	}
+
+	refreshTokenRepository, err := pgsql.NewRefreshTokenRepository(db)
+	if err != nil {
+		log.Fatalln("refresh token repository:", err)
+	}
+
+	sessionRepository, err := pgsql.NewSessionRepository(db)
+	if err != nil {
+		log.Fatalln("session repository:", err)
+	}
+
+	organizationRepository, err := pgsql.NewOrganizationRepository(db)
+	if err != nil {
+		log.Fatalln("organization repository:", err)
+	}
+
+	passwordResetRepository, err := pgsql.NewPasswordResetRepository(db)
+	if err != nil {
+		log.Fatalln("password reset repository:", err)
+	}
+
+	loginAttemptRepository, err := pgsql.NewLoginAttemptRepository(db)
+	if err != nil {
+		log.Fatalln("login attempt repository:", err)
+	}
+
+	twoFactorRepository, err := pgsql.NewTwoFactorRepository(db)
+	if err != nil {
+		log.Fatalln("two factor repository:", err)
+	}
+
+	speechToText := mlclient.NewSpeechToText(cfg, languages)
+	scorer := mlclient.NewCompetencyScorer(cfg, languages)
+
+	pool := queue.NewPool(jobRepository, cfg)
+	pool.Register(repository.TranscribeAnswerJob, queue.TranscribeAnswer(roomRepository, attemptRepository, jobRepository, speechToText, jwtImpl, cfg))
+	pool.OnFailure(repository.TranscribeAnswerJob, queue.TranscribeAnswerFailed(roomRepository, attemptRepository))
+	pool.Register(repository.ScoreRoomJob, queue.ScoreRoom(roomRepository, competencyRepository, questionRepository, scorer))
+	pool.OnFailure(repository.ScoreRoomJob, queue.ScoreRoomFailed(roomRepository))
+	pool.Start()
+	defer pool.Stop()
+
 	c := cron.New()
 
-	// Schedule the cron job to run every two week
-	_, err = c.AddFunc("0 2 1 * *", func() {
-		err := cron_job.TrainModel(feedbackRepository, cfg.SummarizationHostID)
+	// Schedule the training of every language that is retrained periodically
+	for _, l := range languages.All() {
+		if l.TrainingSchedule == "" {
+			continue
+		}
+
+		code := l.Code
+		_, err = c.AddFunc(l.TrainingSchedule, func() {
+			err := cron_job.TrainModel(feedbackRepository, organizationRepository, scorer, code)
+			if err != nil {
+				log.Println("failed to run training_model.go:", err)
+			}
+		})
 		if err != nil {
-			log.Println("failed to run training_model.go:", err)
+			log.Fatalln("failed to schedule cron job:", err)
+		}
+	}
+
+	// Close the rooms that ended without being submitted
+	_, err = c.AddFunc("@every 1m", func() {
+		err := cron_job.ExpireRooms(roomRepository, cfg)
+		if err != nil {
+			log.Println("failed to run expire_rooms.go:", err)
 		}
 	})
 	if err != nil {
@@ -78,9 +173,20 @@ func main() {
 
 	c.Start()
 
-	authMiddleware := middleware.Auth(jwtImpl)
+	revocations := session.NewRevocationList(sessionRepository, cfg.RevocationRefreshInterval)
+	roles := permission.NewRoleCache(userRepository, cfg.RoleRefreshInterval)
+	authMiddleware := middleware.Auth(jwtImpl, revocations, roles)
+	loginGuard := lockout.NewGuard(loginAttemptRepository, cfg)
 
-	roleInterviewerMiddleware := middleware.RBAC(repository.Interviewer, repository.Hrd)
+	can := func(p permission.Permission) func(http.Handler) http.Handler {
+		return middleware.RequirePermission(permissions, p)
+	}
+	roomAccess := func(param string, policy middleware.RoomPolicy) func(http.Handler) http.Handler {
+		return middleware.RoomAccess(permissions, roomRepository, param, policy)
+	}
+	roomGroupAccess := func(param string) func(http.Handler) http.Handler {
+		return middleware.RoomGroupAccess(permissions, roomRepository, param)
+	}
 
 	logMiddleware := middleware.LogMiddleware
 	corsMiddleware := cors.Handler(cors.Options{
@@ -96,59 +202,103 @@ func main() {
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("hiremif backend"))
 	})
-	fs := http.FileServer(http.Dir("data"))
-	r.Handle("/files/*", http.StripPrefix("/files/", fs))
+	r.With(corsMiddleware).Get("/files/*", mediahandler.Get(roomRepository, permissions, files, jwtImpl, authMiddleware))
 	r.With(corsMiddleware).Route("/auth", func(r chi.Router) {
 		r.Get("/verify", authhandler.Verify(userRepository, jwtImpl))
-		r.With(authMiddleware, roleInterviewerMiddleware).Get("/all-emails", authhandler.GetAllEmails(userRepository))
-		r.Post("/register", authhandler.Register(userRepository, jwtImpl, cfg))
-		r.Post("/login", authhandler.Login(userRepository, jwtImpl))
+		r.With(authMiddleware, can(permission.UserRead)).Get("/all-emails", authhandler.GetAllEmails(userRepository))
+		r.Post("/register", authhandler.Register(userRepository, organizationRepository, jwtImpl, cfg))
+		r.Post("/login", authhandler.Login(userRepository, sessionRepository, twoFactorRepository, loginGuard, jwtImpl, cfg))
+		r.Post("/login/2fa", authhandler.LoginTwoFactor(userRepository, sessionRepository, twoFactorRepository, loginGuard, jwtImpl, cfg))
+		r.Post("/login/2fa/setup", authhandler.LoginTwoFactorSetup(userRepository, twoFactorRepository, jwtImpl))
+		r.Post("/refresh", authhandler.Refresh(refreshTokenRepository, sessionRepository, revocations, jwtImpl))
+		r.Post("/forgot-password", authhandler.ForgotPassword(userRepository, passwordResetRepository, cfg))
+		r.Post("/reset-password", authhandler.ResetPassword(passwordResetRepository, sessionRepository, revocations))
+		r.With(authMiddleware).Post("/logout", authhandler.Logout(sessionRepository, revocations))
+		r.With(authMiddleware).Get("/sessions", authhandler.GetSessions(sessionRepository))
+		r.With(authMiddleware).Delete("/sessions/{id}", authhandler.DeleteSession(sessionRepository, revocations))
 		r.Get("/verify-email", authhandler.VerifyEmail(userRepository, jwtImpl))
-		r.With(authMiddleware, roleInterviewerMiddleware).Get("/check/{email}", authhandler.EmailCheck(userRepository))
+		r.Post("/resend-verification", authhandler.ResendVerification(userRepository, jwtImpl, cfg))
+		r.With(authMiddleware, can(permission.UserRead)).Get("/check/{email}", authhandler.EmailCheck(userRepository))
 		r.With(authMiddleware).Get("/me", authhandler.Profile(userRepository))
 		r.With(authMiddleware).Put("/me", authhandler.UpdateProfile(userRepository))
-		r.With(authMiddleware).Put("/me/password", authhandler.UpdatePassword(userRepository))
+		r.With(authMiddleware).Put("/me/password", authhandler.UpdatePassword(userRepository, sessionRepository, revocations))
+		r.With(authMiddleware).Get("/me/2fa", authhandler.GetTwoFactor(twoFactorRepository))
+		r.With(authMiddleware).Post("/me/2fa", authhandler.SetupTwoFactor(userRepository, twoFactorRepository))
+		r.With(authMiddleware).Delete("/me/2fa", authhandler.DisableTwoFactor(userRepository, twoFactorRepository))
+		r.With(authMiddleware).Post("/me/2fa/verify", authhandler.VerifyTwoFactor(twoFactorRepository))
+		r.With(authMiddleware).Post("/me/2fa/recovery-codes", authhandler.RegenerateRecoveryCodes(twoFactorRepository))
 	})
 
-	r.With(corsMiddleware, authMiddleware, roleInterviewerMiddleware).
+	r.With(corsMiddleware, authMiddleware, can(permission.UserManage)).
+		Route("/user", func(r chi.Router) {
+			r.Get("/", userhandler.GetAll(userRepository))
+			r.Post("/invite", userhandler.Invite(userRepository, passwordResetRepository, organizationRepository, permissions, cfg))
+			r.Put("/{id}/role", userhandler.UpdateRole(userRepository, roles))
+			r.Post("/{id}/deactivate", userhandler.Deactivate(userRepository, sessionRepository, revocations, roles))
+			r.Post("/{id}/activate", userhandler.Activate(userRepository, roles))
+			r.Post("/{id}/unlock", userhandler.Unlock(userRepository, loginGuard))
+			r.Delete("/{id}/2fa", userhandler.ResetTwoFactor(userRepository, twoFactorRepository))
+			r.Get("/2fa-roles", userhandler.GetTwoFactorRoles(twoFactorRepository))
+			r.Put("/2fa-roles", userhandler.UpdateTwoFactorRoles(twoFactorRepository))
+		})
+
+	r.With(corsMiddleware, authMiddleware, can(permission.OrganizationManage)).
+		Route("/organization", func(r chi.Router) {
+			r.Get("/", organizationhandler.GetAll(organizationRepository))
+			r.Post("/", organizationhandler.Create(organizationRepository))
+		})
+
+	r.With(corsMiddleware, authMiddleware).
 		Route("/question", func(r chi.Router) {
-			r.Post("/", questionhandler.Create(questionRepository))
-			r.Get("/", questionhandler.GetAll(questionRepository))
-			r.Get("/{id}", questionhandler.GetOne(questionRepository))
-			r.Put("/{id}", questionhandler.Update(questionRepository))
-			r.Delete("/{id}", questionhandler.Delete(questionRepository))
+			r.With(can(permission.QuestionWrite)).Post("/", questionhandler.Create(questionRepository))
+			r.With(can(permission.QuestionRead)).Get("/", questionhandler.GetAll(questionRepository))
+			r.With(can(permission.QuestionRead)).Get("/{id}", questionhandler.GetOne(questionRepository))
+			r.With(can(permission.QuestionWrite)).Put("/{id}", questionhandler.Update(questionRepository))
+			r.With(can(permission.QuestionWrite)).Delete("/{id}", questionhandler.Delete(questionRepository))
 		})
 
-	r.With(corsMiddleware, authMiddleware, roleInterviewerMiddleware).
+	r.With(corsMiddleware, authMiddleware).
 		Route("/competency", func(r chi.Router) {
-			r.Post("/", competencyhandler.Create(competencyRepository))
-			r.Get("/", competencyhandler.GetAll(competencyRepository))
-			r.Get("/only", competencyhandler.GetAllCompetencyOnly(competencyRepository))
-			r.Get("/{id}", competencyhandler.GetOne(competencyRepository))
-			r.Put("/{id}", competencyhandler.Update(competencyRepository))
-			r.Delete("/{id}", competencyhandler.Delete(competencyRepository))
+			r.With(can(permission.CompetencyWrite)).Post("/", competencyhandler.Create(competencyRepository))
+			r.With(can(permission.CompetencyRead)).Get("/", competencyhandler.GetAll(competencyRepository))
+			r.With(can(permission.CompetencyRead)).Get("/only", competencyhandler.GetAllCompetencyOnly(competencyRepository))
+			r.With(can(permission.CompetencyRead)).Get("/{id}", competencyhandler.GetOne(competencyRepository))
+			r.With(can(permission.CompetencyWrite)).Put("/{id}", competencyhandler.Update(competencyRepository))
+			r.With(can(permission.CompetencyWrite)).Delete("/{id}", competencyhandler.Delete(competencyRepository))
 		})
 
 	r.With(corsMiddleware, authMiddleware).Route("/room", func(r chi.Router) {
 		// r.Get("/", roomhandler.GetAll(roomRepository))
-		r.With(roleInterviewerMiddleware).Post("/group", roomhandler.CreateRoom(roomRepository, userRepository, cfg))
-		r.Get("/group", roomhandler.GetAllRoomGroup(roomRepository))
-		r.Get("/group/{id}", roomhandler.GetOneRoomGroup(roomRepository))
-		r.Get("/{id}", roomhandler.GetOneRoom(roomRepository, questionRepository, competencyRepository))
-		r.Post("/{roomId}/{questionId}", roomhandler.Answer(roomRepository, competencyRepository, questionRepository, feedbackRepository, cfg))
-		r.Get("/get-question/{roomId}/{questionId}", roomhandler.GetOneQuestionRoom(roomRepository))
-		r.Put("/update-current-question/{roomId}/{questionId}", roomhandler.UpdateQuestionCond(roomRepository))
-		r.Post("/{roomId}/finish-answer", roomhandler.FinishAnswer(roomRepository))
-		r.With(roleInterviewerMiddleware).Post("/", roomhandler.CreateRoom(roomRepository, userRepository, cfg))
-		r.With(roleInterviewerMiddleware).Post("/group", roomhandler.CreateRoomGroup(roomRepository, userRepository, cfg))
-		r.With(roleInterviewerMiddleware).Post("/{id}/review", roomhandler.Review(roomRepository))
-		r.With(roleInterviewerMiddleware).Post("/update-questions-competencies", roomhandler.UpdateQuestionsAndCompetenciesRoom(roomRepository, userRepository, cfg))
-		r.With(roleInterviewerMiddleware).Delete("/{id}", roomhandler.Delete(roomRepository))
+		r.With(can(permission.RoomCreate)).Post("/group", roomhandler.CreateRoom(roomRepository, userRepository, languages, cfg))
+		r.Get("/group", roomhandler.GetAllRoomGroup(roomRepository, permissions))
+		r.With(roomGroupAccess("id")).Get("/group/{id}", roomhandler.GetOneRoomGroup(roomRepository))
+		r.With(roomAccess("id", middleware.RoomParticipant)).Get("/{id}", roomhandler.GetOneRoom(roomRepository, questionRepository, competencyRepository, cfg))
+		r.With(roomAccess("roomId", middleware.RoomCandidate)).Post("/{roomId}/{questionId}", roomhandler.Answer(roomRepository, attemptRepository, jobRepository, cfg))
+		r.With(roomAccess("roomId", middleware.RoomCandidate)).Post("/{roomId}/{questionId}/upload", roomhandler.Upload(roomRepository, attemptRepository, jobRepository, files, cfg))
+		r.With(roomAccess("roomId", middleware.RoomParticipant)).Get("/{roomId}/{questionId}/attempts", roomhandler.GetAttempts(attemptRepository))
+		r.With(roomAccess("roomId", middleware.RoomCandidate)).Put("/{roomId}/{questionId}/attempts/{attemptId}", roomhandler.ChooseAttempt(roomRepository, attemptRepository, jobRepository, cfg))
+		r.With(roomAccess("roomId", middleware.RoomParticipant)).Get("/get-question/{roomId}/{questionId}", roomhandler.GetOneQuestionRoom(roomRepository))
+		r.With(roomAccess("roomId", middleware.RoomCandidate)).Put("/update-current-question/{roomId}/{questionId}", roomhandler.UpdateQuestionCond(roomRepository, cfg))
+		r.With(roomAccess("roomId", middleware.RoomCandidate)).Post("/{roomId}/finish-answer", roomhandler.FinishAnswer(roomRepository, cfg))
+		r.With(roomAccess("id", middleware.RoomCandidate)).Post("/{id}/session", roomhandler.StartSession(roomRepository, questionRepository, cfg))
+		r.With(can(permission.RoomCreate)).Post("/", roomhandler.CreateRoom(roomRepository, userRepository, languages, cfg))
+		r.With(can(permission.RoomCreate)).Post("/group", roomhandler.CreateRoomGroup(roomRepository, userRepository, languages, cfg))
+		r.With(can(permission.RoomReview), roomAccess("id", middleware.RoomStaff)).Post("/{id}/review", roomhandler.Review(roomRepository))
+		r.With(can(permission.RoomManage), roomAccess("id", middleware.RoomStaff)).Post("/{id}/cancel", roomhandler.Cancel(roomRepository))
+		r.With(can(permission.RoomManage), roomAccess("id", middleware.RoomStaff)).Put("/{id}/questions/order", roomhandler.ReorderQuestions(roomRepository))
+		r.With(can(permission.RoomReview), roomAccess("id", middleware.RoomStaff)).Get("/{id}/processing", roomhandler.GetProcessing(roomRepository))
+		r.With(can(permission.RoomReview), roomAccess("id", middleware.RoomStaff)).Post("/{id}/rescore", roomhandler.Rescore(roomRepository, jobRepository, cfg))
+		r.With(can(permission.RoomReview), roomAccess("id", middleware.RoomStaff)).Get("/{id}/results", roomhandler.GetResultHistory(roomRepository))
+		r.With(can(permission.RoomReview), roomAccess("id", middleware.RoomStaff)).Get("/{id}/timeline", roomhandler.GetTimeline(roomRepository))
+		r.With(can(permission.RoomCreate)).Post("/update-questions-competencies", roomhandler.UpdateQuestionsAndCompetenciesRoom(roomRepository, userRepository, permissions, cfg))
+		r.With(can(permission.RoomManage), roomAccess("id", middleware.RoomStaff)).Delete("/{id}", roomhandler.Delete(roomRepository))
 	})
 
-	r.With(corsMiddleware, authMiddleware, roleInterviewerMiddleware).Route("/feedback", func(r chi.Router) {
-		r.Get("/", feedbackhandler.GetAllNeedFeedback(feedbackRepository, cfg.SummarizationHostEN))
-		r.Put("/{id}", feedbackhandler.UpdateFeedback(feedbackRepository, cfg.SummarizationHostEN))
+	r.With(corsMiddleware, authMiddleware).Get("/language", languagehandler.GetAll(languages))
+
+	r.With(corsMiddleware, authMiddleware, can(permission.FeedbackLabel)).Route("/feedback", func(r chi.Router) {
+		r.Get("/", feedbackhandler.GetAllNeedFeedback(feedbackRepository, scorer, languages))
+		r.Put("/{id}", feedbackhandler.UpdateFeedback(feedbackRepository, scorer, languages))
 	})
 
 	log.Printf("Server is listening on port %s", cfg.APIPort)
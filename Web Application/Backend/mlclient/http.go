@@ -0,0 +1,97 @@
+package mlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Minute
+
+// maxErrorBody caps how much of a failed response ends up in the error message.
+const maxErrorBody = 512
+
+type httpClient struct {
+	client *http.Client
+}
+
+func newHTTPClient(timeout time.Duration) httpClient {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return httpClient{client: &http.Client{Timeout: timeout}}
+}
+
+// do sends the request and returns the raw response body of a 2xx response.
+func (c httpClient) do(ctx context.Context, op, method, url string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, &Error{Op: op, Err: err}
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, &Error{Op: op, Err: err}
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// the caller gave up, there is nothing left to retry
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			return nil, &Error{Op: op, Err: err}
+		}
+
+		return nil, &Error{Op: op, Temporary: true, Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &Error{Op: op, Temporary: true, Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(respBody) > maxErrorBody {
+			respBody = respBody[:maxErrorBody]
+		}
+
+		return nil, &Error{
+			Op:         op,
+			StatusCode: resp.StatusCode,
+			Temporary:  resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout,
+			Err:        fmt.Errorf("%s", bytes.TrimSpace(respBody)),
+		}
+	}
+
+	return respBody, nil
+}
+
+func (c httpClient) doJSON(ctx context.Context, op, method, url string, payload, result interface{}) error {
+	body, err := c.do(ctx, op, method, url, payload)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return &Error{Op: op, Err: fmt.Errorf("decode response: %w", err)}
+	}
+
+	return nil
+}
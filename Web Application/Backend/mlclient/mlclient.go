@@ -0,0 +1,76 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type SpeechToText interface {
+	// Transcribe downloads the recording behind the link and returns its transcript.
+	Transcribe(ctx context.Context, language, link string) (string, error)
+}
+
+type CompetencyScorer interface {
+	Predict(ctx context.Context, language string, req PredictRequest) (*PredictResponse, error)
+	// ToLabel asks the model which stored feedback of the organization it
+	// wants labeled next.
+	ToLabel(ctx context.Context, language, orgId string) (*ToLabelResponse, error)
+	// Train retrains the model with the labeled feedback of the organization.
+	Train(ctx context.Context, language, orgId string) error
+}
+
+type TranscribeRequest struct {
+	Link string `json:"link"`
+}
+
+type PredictRequest struct {
+	Transcripts    []string   `json:"transcripts"`
+	CompetenceSets [][]string `json:"competence_sets"`
+}
+
+type PredictResponse struct {
+	Scores [][]float64 `json:"scores,omitempty"`
+	// ModelVersion identifies the trained model that produced the scores,
+	// it is empty when the host does not report one.
+	ModelVersion string `json:"model_version,omitempty"`
+}
+
+type ToLabelResponse struct {
+	IDs    []string  `json:"id"`
+	Scores []float64 `json:"scores"`
+}
+
+// Error is returned by every client call. Temporary is set when the same
+// request may succeed later: the host was unreachable, timed out, was
+// overloaded or failed with a server error.
+type Error struct {
+	Op         string
+	StatusCode int
+	Temporary  bool
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: status code %d: %v", e.Op, e.StatusCode, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// IsTemporary reports whether retrying the call that returned err may succeed.
+func IsTemporary(err error) bool {
+	var mlErr *Error
+	if errors.As(err, &mlErr) {
+		return mlErr.Temporary
+	}
+
+	return false
+}
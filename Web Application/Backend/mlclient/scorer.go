@@ -0,0 +1,60 @@
+package mlclient
+
+import (
+	"context"
+	"interview/summarization/config"
+	"interview/summarization/language"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type competencyScorer struct {
+	httpClient
+	languages *language.Registry
+}
+
+func NewCompetencyScorer(cfg config.Config, languages *language.Registry) CompetencyScorer {
+	return &competencyScorer{
+		httpClient: newHTTPClient(time.Duration(cfg.SummarizationTimeout) * time.Second),
+		languages:  languages,
+	}
+}
+
+func (s *competencyScorer) Predict(ctx context.Context, code string, req PredictRequest) (*PredictResponse, error) {
+	l, ok := s.languages.Get(code)
+	if !ok {
+		return nil, &Error{Op: "predict", Err: ErrUnsupportedLanguage}
+	}
+
+	res := &PredictResponse{}
+	if err := s.doJSON(ctx, "predict", http.MethodPost, l.ScorerHost+l.PredictPath(), req, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *competencyScorer) ToLabel(ctx context.Context, code, orgId string) (*ToLabelResponse, error) {
+	l, ok := s.languages.Get(code)
+	if !ok {
+		return nil, &Error{Op: "to label", Err: ErrUnsupportedLanguage}
+	}
+
+	res := &ToLabelResponse{}
+	if err := s.doJSON(ctx, "to label", http.MethodGet, l.ScorerHost+"/to-label?org_id="+url.QueryEscape(orgId), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *competencyScorer) Train(ctx context.Context, code, orgId string) error {
+	l, ok := s.languages.Get(code)
+	if !ok {
+		return &Error{Op: "train", Err: ErrUnsupportedLanguage}
+	}
+
+	_, err := s.do(ctx, "train", http.MethodPost, l.ScorerHost+"/train?org_id="+url.QueryEscape(orgId), nil)
+	return err
+}
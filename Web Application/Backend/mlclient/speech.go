@@ -0,0 +1,35 @@
+package mlclient
+
+import (
+	"context"
+	"interview/summarization/config"
+	"interview/summarization/language"
+	"net/http"
+	"time"
+)
+
+type speechToText struct {
+	httpClient
+	languages *language.Registry
+}
+
+func NewSpeechToText(cfg config.Config, languages *language.Registry) SpeechToText {
+	return &speechToText{
+		httpClient: newHTTPClient(time.Duration(cfg.SpeechToTextTimeout) * time.Second),
+		languages:  languages,
+	}
+}
+
+func (s *speechToText) Transcribe(ctx context.Context, code, link string) (string, error) {
+	l, ok := s.languages.Get(code)
+	if !ok {
+		return "", &Error{Op: "speech to text", Err: ErrUnsupportedLanguage}
+	}
+
+	transcript, err := s.do(ctx, "speech to text", http.MethodPost, l.SpeechToTextURL(), TranscribeRequest{Link: link})
+	if err != nil {
+		return "", err
+	}
+
+	return string(transcript), nil
+}
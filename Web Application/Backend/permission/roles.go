@@ -0,0 +1,80 @@
+package permission
+
+import (
+	"context"
+	"interview/summarization/repository"
+	"sync"
+	"time"
+)
+
+const defaultRoleRefreshInterval = 30 // seconds
+
+type cachedUser struct {
+	user     repository.User
+	loadedAt time.Time
+}
+
+// RoleCache looks up the current organization, role and status of users,
+// so a role change or a deactivation applies to the tokens already handed
+// out. A lookup is kept for the refresh interval, changes made by this
+// server apply at once. Expired lookups are swept once per interval, so only
+// the users active lately stay in memory.
+type RoleCache struct {
+	userRepository repository.UserRepository
+	interval       time.Duration
+
+	mu      sync.Mutex
+	users   map[string]cachedUser
+	sweptAt time.Time
+}
+
+func NewRoleCache(userRepository repository.UserRepository, refreshInterval int) *RoleCache {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRoleRefreshInterval
+	}
+
+	return &RoleCache{
+		userRepository: userRepository,
+		interval:       time.Duration(refreshInterval) * time.Second,
+		users:          map[string]cachedUser{},
+		sweptAt:        time.Now(),
+	}
+}
+
+// Lookup returns the id, organization, role and status of the user.
+func (c *RoleCache) Lookup(ctx context.Context, userId string) (repository.User, error) {
+	c.mu.Lock()
+	cached, ok := c.users[userId]
+	c.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) <= c.interval {
+		return cached.user, nil
+	}
+
+	user, err := c.userRepository.SelectOrgRoleStatusByID(ctx, userId)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	if now.Sub(c.sweptAt) > c.interval {
+		for id, cached := range c.users {
+			if now.Sub(cached.loadedAt) > c.interval {
+				delete(c.users, id)
+			}
+		}
+		c.sweptAt = now
+	}
+	c.users[userId] = cachedUser{user: *user, loadedAt: now}
+	c.mu.Unlock()
+
+	return *user, nil
+}
+
+// Forget drops the cached lookup of a user whose role or status changed.
+func (c *RoleCache) Forget(userId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.users, userId)
+}
@@ -0,0 +1,109 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"strings"
+)
+
+type Permission string
+
+const (
+	UserRead        = Permission("user:read")
+	UserManage      = Permission("user:manage")
+	QuestionRead    = Permission("question:read")
+	QuestionWrite   = Permission("question:write")
+	CompetencyRead  = Permission("competency:read")
+	CompetencyWrite = Permission("competency:write")
+	RoomCreate      = Permission("room:create")
+	RoomManage      = Permission("room:manage")
+	RoomReview      = Permission("room:review")
+	FeedbackLabel   = Permission("feedback:label")
+	// RoomOversee opens every room of the organization, not only the ones
+	// the user interviews.
+	RoomOversee = Permission("room:oversee")
+	// OrganizationManage creates organizations and invites users into any
+	// of them, no role is granted it by default.
+	OrganizationManage = Permission("organization:manage")
+)
+
+var permissions = map[Permission]bool{
+	UserRead:           true,
+	UserManage:         true,
+	QuestionRead:       true,
+	QuestionWrite:      true,
+	CompetencyRead:     true,
+	CompetencyWrite:    true,
+	RoomCreate:         true,
+	RoomManage:         true,
+	RoomReview:         true,
+	RoomOversee:        true,
+	FeedbackLabel:      true,
+	OrganizationManage: true,
+}
+
+// defaultRoles grants interviewers and HRD the same rights they always had,
+// only HRD manage the users and oversee every room.
+var defaultRoles = map[string][]Permission{
+	string(repository.Hrd): {
+		UserRead, UserManage, QuestionRead, QuestionWrite, CompetencyRead, CompetencyWrite,
+		RoomCreate, RoomManage, RoomReview, RoomOversee, FeedbackLabel,
+	},
+	string(repository.Interviewer): {
+		UserRead, QuestionRead, QuestionWrite, CompetencyRead, CompetencyWrite,
+		RoomCreate, RoomManage, RoomReview, FeedbackLabel,
+	},
+	string(repository.Interviewee): {},
+}
+
+// Table maps every role to the permissions it is granted.
+type Table struct {
+	roles map[repository.UserRole]map[Permission]bool
+}
+
+// NewTable builds the table from the ROLE_PERMISSIONS config, a JSON object
+// of role to permission list. Roles left out of it keep their default
+// permissions.
+func NewTable(cfg config.Config) (*Table, error) {
+	roles := map[string][]Permission{}
+	for role, granted := range defaultRoles {
+		roles[role] = granted
+	}
+
+	if strings.TrimSpace(cfg.RolePermissions) != "" {
+		configured := map[string][]Permission{}
+		if err := json.Unmarshal([]byte(cfg.RolePermissions), &configured); err != nil {
+			return nil, fmt.Errorf("invalid ROLE_PERMISSIONS config: %w", err)
+		}
+		for role, granted := range configured {
+			roles[strings.ToUpper(strings.TrimSpace(role))] = granted
+		}
+	}
+
+	t := &Table{
+		roles: make(map[repository.UserRole]map[Permission]bool, len(roles)),
+	}
+	for role, granted := range roles {
+		userRole, ok := repository.UserRoleMapper(role)
+		if !ok {
+			return nil, fmt.Errorf("invalid ROLE_PERMISSIONS config: unknown role %s", role)
+		}
+
+		t.roles[userRole] = make(map[Permission]bool, len(granted))
+		for _, p := range granted {
+			if !permissions[p] {
+				return nil, fmt.Errorf("invalid ROLE_PERMISSIONS config: unknown permission %s", p)
+			}
+
+			t.roles[userRole][p] = true
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Table) Has(role repository.UserRole, p Permission) bool {
+	return t.roles[role][p]
+}
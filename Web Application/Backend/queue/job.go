@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"interview/summarization/mlclient"
+	"interview/summarization/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultMaxAttempts = 5
+
+type TranscribeAnswerPayload struct {
+	RoomID     string `json:"room_id"`
+	QuestionID string `json:"question_id"`
+	AttemptID  string `json:"attempt_id,omitempty"`
+	FileLink   string `json:"file_link"`
+	Language   string `json:"language"`
+}
+
+type ScoreRoomPayload struct {
+	RoomID   string `json:"room_id"`
+	Language string `json:"language"`
+	Rescore  bool   `json:"rescore,omitempty"`
+}
+
+// Enqueue stores a job for the pool and reports whether it was queued. Jobs
+// with the same non-empty dedupe key are only queued once while one of them
+// is still waiting, a job queued while another one runs waits for it.
+func Enqueue(
+	ctx context.Context,
+	jobRepository repository.JobRepository,
+	jobType repository.JobType,
+	dedupeKey string,
+	maxAttempts int,
+	payload interface{},
+) (bool, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	job := &repository.Job{
+		ID:          uuid.NewString(),
+		Type:        jobType,
+		Payload:     body,
+		DedupeKey:   sql.NullString{String: dedupeKey, Valid: dedupeKey != ""},
+		MaxAttempts: maxAttempts,
+		RunAt:       time.Now().UTC(),
+	}
+
+	return jobRepository.Enqueue(ctx, job)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error that retrying cannot fix, the job goes straight
+// to the dead-letter state.
+func Permanent(err error) error {
+	return &permanentError{err}
+}
+
+func isPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// mlError only keeps retrying the failures of the ML hosts that may go away
+// on their own, a rejected request will be rejected again.
+func mlError(err error) error {
+	if mlclient.IsTemporary(err) {
+		return err
+	}
+
+	return Permanent(err)
+}
+
+func decodePayload(job *repository.Job, payload interface{}) error {
+	if err := json.Unmarshal(job.Payload, payload); err != nil {
+		return Permanent(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/config"
+	"interview/summarization/repository"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPoolSize      = 4
+	defaultLeaseDuration = 10 * time.Minute
+	defaultPollInterval  = 2 * time.Second
+
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 30 * time.Minute
+)
+
+type Handler func(ctx context.Context, job *repository.Job) error
+
+// FailureHandler is told about every failed attempt of a job, dead is set
+// once the job will not be retried anymore.
+type FailureHandler func(ctx context.Context, job *repository.Job, err error, dead bool)
+
+type Pool struct {
+	jobRepository repository.JobRepository
+	handlers      map[repository.JobType]Handler
+	failures      map[repository.JobType]FailureHandler
+	size          int
+	leaseDuration time.Duration
+	pollInterval  time.Duration
+	workerID      string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPool(jobRepository repository.JobRepository, cfg config.Config) *Pool {
+	p := &Pool{
+		jobRepository: jobRepository,
+		handlers:      map[repository.JobType]Handler{},
+		failures:      map[repository.JobType]FailureHandler{},
+		size:          cfg.WorkerPoolSize,
+		leaseDuration: time.Duration(cfg.JobLeaseDuration) * time.Second,
+		pollInterval:  time.Duration(cfg.JobPollInterval) * time.Second,
+	}
+
+	if p.size <= 0 {
+		p.size = defaultPoolSize
+	}
+	if p.leaseDuration <= 0 {
+		p.leaseDuration = defaultLeaseDuration
+	}
+	if p.pollInterval <= 0 {
+		p.pollInterval = defaultPollInterval
+	}
+
+	hostname, _ := os.Hostname()
+	p.workerID = fmt.Sprintf("%s-%s", hostname, uuid.NewString())
+
+	return p
+}
+
+func (p *Pool) Register(jobType repository.JobType, handler Handler) {
+	p.handlers[jobType] = handler
+}
+
+func (p *Pool) OnFailure(jobType repository.JobType, handler FailureHandler) {
+	p.failures[jobType] = handler
+}
+
+func (p *Pool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	types := make([]repository.JobType, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+
+	for i := 0; i < p.size; i++ {
+		p.wg.Add(1)
+		go func(workerID string) {
+			defer p.wg.Done()
+			p.work(ctx, workerID, types)
+		}(fmt.Sprintf("%s-%d", p.workerID, i))
+	}
+}
+
+// Stop stops leasing new jobs and waits for the running ones to finish.
+func (p *Pool) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+func (p *Pool) work(ctx context.Context, workerID string, types []repository.JobType) {
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain the queue before going back to sleep
+		for p.runNext(ctx, workerID, types) {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pool) runNext(ctx context.Context, workerID string, types []repository.JobType) bool {
+	job, err := p.jobRepository.Lease(ctx, workerID, types, p.leaseDuration)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) && ctx.Err() == nil {
+			log.Println("queue: failed to lease job:", err)
+		}
+		return false
+	}
+
+	// the job keeps running on shutdown, it must not outlive its lease
+	jobCtx, cancel := context.WithTimeout(context.Background(), p.leaseDuration)
+	defer cancel()
+
+	jobErr := p.handle(jobCtx, job)
+	if jobErr == nil {
+		if err := p.jobRepository.Complete(jobCtx, job); err != nil {
+			log.Printf("queue: failed to complete job %s: %v", job.ID, err)
+		}
+		return true
+	}
+
+	dead := isPermanent(jobErr) || job.Attempts >= job.MaxAttempts
+	if dead {
+		log.Printf("queue: job %s (%s) is dead after %d attempts: %v", job.ID, job.Type, job.Attempts, jobErr)
+		err = p.jobRepository.Bury(jobCtx, job, jobErr.Error())
+	} else {
+		log.Printf("queue: job %s (%s) failed on attempt %d: %v", job.ID, job.Type, job.Attempts, jobErr)
+		err = p.jobRepository.Retry(jobCtx, job, time.Now().UTC().Add(backoff(job.Attempts)), jobErr.Error())
+	}
+	if err != nil {
+		log.Printf("queue: failed to record failure of job %s: %v", job.ID, err)
+		return true
+	}
+
+	if onFailure, ok := p.failures[job.Type]; ok {
+		onFailure(jobCtx, job, jobErr, dead)
+	}
+
+	return true
+}
+
+func (p *Pool) handle(ctx context.Context, job *repository.Job) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+
+	handler, ok := p.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler registered for job type %s", job.Type))
+	}
+
+	return handler(ctx, job)
+}
+
+func backoff(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,177 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"interview/summarization/mlclient"
+	"interview/summarization/repository"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+func ScoreRoom(
+	roomRepository repository.RoomRepository,
+	competencyRepository repository.CompetencyRepository,
+	questionRepository repository.QuestionRepository,
+	scorer mlclient.CompetencyScorer,
+) Handler {
+	return func(ctx context.Context, job *repository.Job) error {
+		payload := ScoreRoomPayload{}
+		if err := decodePayload(job, &payload); err != nil {
+			return err
+		}
+
+		err := roomRepository.UpdateRoomAnswersStatus(ctx, payload.RoomID, repository.AnswerScoring, "")
+		if err != nil {
+			return err
+		}
+
+		// a previous attempt may have stored the result before losing its lease
+		isScored, err := roomRepository.IsScoredByJob(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if isScored {
+			return roomRepository.UpdateRoomAnswersStatus(ctx, payload.RoomID, repository.AnswerScored, "")
+		}
+
+		answer, err := roomRepository.GetResultQuestions(ctx, payload.RoomID)
+		if err != nil {
+			return err
+		}
+		questions, err := questionRepository.SelectAllByRoomID(ctx, payload.RoomID)
+		if err != nil {
+			return err
+		}
+		competencies, err := competencyRepository.SelectAllByRoomID(ctx, payload.RoomID)
+		if err != nil {
+			return err
+		}
+
+		var mapKamus [][]string
+		var transcripts []string
+		for _, c := range competencies {
+			var mapLevel []string
+			transcript := ""
+			for _, q := range questions {
+				for _, ql := range q.Labels {
+					if ql.CompetencyID == c.ID {
+						transcript += answer[q.ID] + " "
+					}
+				}
+			}
+			for _, cl := range c.Levels {
+				mapLevel = append(mapLevel, cl.Description)
+			}
+			mapKamus = append(mapKamus, mapLevel)
+			transcripts = append(transcripts, transcript)
+		}
+
+		predictReq := mlclient.PredictRequest{
+			Transcripts:    transcripts,
+			CompetenceSets: mapKamus,
+		}
+		transcriptHash, err := hashPredictRequest(predictReq)
+		if err != nil {
+			return Permanent(err)
+		}
+
+		res, err := scorer.Predict(ctx, payload.Language, predictReq)
+		if err != nil {
+			return mlError(err)
+		}
+		if len(res.Scores) != len(competencies) {
+			return Permanent(fmt.Errorf("predict: got %d score sets for %d competencies", len(res.Scores), len(competencies)))
+		}
+
+		// for result
+		var competencyRes []string
+		var levelRes []string
+		var resultRes []float64
+
+		// for feedback
+		var feedback []*repository.Feedback
+
+		for i, c := range competencies {
+			if len(res.Scores[i]) != len(c.Levels) {
+				return Permanent(fmt.Errorf("predict: got %d scores for %d levels of %s", len(res.Scores[i]), len(c.Levels), c.Competency))
+			}
+			if len(c.Levels) == 0 {
+				continue
+			}
+
+			maxIndex := 0
+			maxScore := -1.0
+			for j, cl := range c.Levels {
+				competencyRes = append(competencyRes, c.Competency)
+				levelRes = append(levelRes, cl.Level)
+				resultRes = append(resultRes, res.Scores[i][j])
+				if res.Scores[i][j] > maxScore {
+					maxScore = res.Scores[i][j]
+					maxIndex = j
+				}
+			}
+			feedback = append(feedback, &repository.Feedback{
+				ID:           uuid.NewString(),
+				CompetencyID: c.ID,
+				Transcript:   transcripts[i],
+				LabelResult:  c.Levels[maxIndex].ID,
+			})
+		}
+
+		resultSet := &repository.ResultSet{
+			ID:             uuid.NewString(),
+			RoomID:         payload.RoomID,
+			JobID:          sql.NullString{String: job.ID, Valid: true},
+			ModelVersion:   sql.NullString{String: res.ModelVersion, Valid: res.ModelVersion != ""},
+			Language:       payload.Language,
+			TranscriptHash: transcriptHash,
+		}
+		// the transcripts of a rescored room are already waiting for their labels
+		if !payload.Rescore {
+			resultSet.Feedback = feedback
+		}
+		if err := roomRepository.InsertResult(ctx, resultSet, competencyRes, levelRes, resultRes); err != nil {
+			return err
+		}
+
+		return roomRepository.UpdateRoomAnswersStatus(ctx, payload.RoomID, repository.AnswerScored, "")
+	}
+}
+
+// hashPredictRequest fingerprints the scorer input, two runs with the same
+// hash only differ by the model that scored them.
+func hashPredictRequest(req mlclient.PredictRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func ScoreRoomFailed(roomRepository repository.RoomRepository) FailureHandler {
+	return func(ctx context.Context, job *repository.Job, jobErr error, dead bool) {
+		payload := ScoreRoomPayload{}
+		if err := decodePayload(job, &payload); err != nil {
+			return
+		}
+
+		// the transcripts are kept, only the scoring is waiting for its retry
+		status := repository.AnswerTranscribed
+		if dead {
+			status = repository.AnswerFailed
+		}
+
+		lastError := fmt.Sprintf("scoring attempt %d: %s", job.Attempts, jobErr)
+		if err := roomRepository.UpdateRoomAnswersStatus(ctx, payload.RoomID, status, lastError); err != nil {
+			log.Printf("queue: failed to record answer status of job %s: %v", job.ID, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/config"
+	"interview/summarization/mlclient"
+	"interview/summarization/repository"
+	"interview/summarization/token"
+	"log"
+	"strings"
+	"time"
+)
+
+const defaultSignedURLExpire = 15 // minutes
+
+func TranscribeAnswer(
+	roomRepository repository.RoomRepository,
+	attemptRepository repository.AttemptRepository,
+	jobRepository repository.JobRepository,
+	speechToText mlclient.SpeechToText,
+	signer token.URLSigner,
+	cfg config.Config,
+) Handler {
+	return func(ctx context.Context, job *repository.Job) error {
+		payload := TranscribeAnswerPayload{}
+		if err := decodePayload(job, &payload); err != nil {
+			return err
+		}
+
+		// jobs queued before answers had attempts always count
+		attempt := &repository.AnswerAttempt{Selected: true}
+		if payload.AttemptID != "" {
+			var err error
+			attempt, err = attemptRepository.SelectOneByID(ctx, payload.AttemptID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return Permanent(err)
+				}
+
+				return err
+			}
+		}
+
+		if attempt.Selected {
+			err := roomRepository.UpdateAnswerStatus(ctx, payload.RoomID, payload.QuestionID, repository.AnswerTranscribing, "")
+			if err != nil {
+				return answerStatusError(err)
+			}
+		}
+
+		// an attempt chosen again keeps the transcript it already has
+		transcript := attempt.Transcript.String
+		if !attempt.Transcript.Valid {
+			var err error
+			transcript, err = speechToText.Transcribe(ctx, payload.Language, signFileLink(signer, cfg, payload.FileLink))
+			if err != nil {
+				return mlError(err)
+			}
+
+			if payload.AttemptID != "" {
+				if err := attemptRepository.UpdateTranscript(ctx, payload.AttemptID, transcript); err != nil {
+					return err
+				}
+			}
+		}
+
+		// the candidate may have picked another attempt in the meantime
+		if payload.AttemptID != "" {
+			attempt, err := attemptRepository.SelectOneByID(ctx, payload.AttemptID)
+			if err != nil {
+				return err
+			}
+			if !attempt.Selected {
+				return nil
+			}
+		}
+
+		err := roomRepository.UpdateAnswerStatus(ctx, payload.RoomID, payload.QuestionID, repository.AnswerTranscribing, "")
+		if err != nil {
+			return answerStatusError(err)
+		}
+
+		err = roomRepository.InsertTranscript(ctx, payload.RoomID, payload.QuestionID, payload.FileLink, transcript)
+		if err != nil {
+			return err
+		}
+
+		err = roomRepository.UpdateAnswerStatus(ctx, payload.RoomID, payload.QuestionID, repository.AnswerTranscribed, "")
+		if err != nil {
+			return answerStatusError(err)
+		}
+
+		isAnswered, err := roomRepository.IsAnswered(ctx, payload.RoomID)
+		if err != nil {
+			return err
+		}
+		if !isAnswered {
+			return nil
+		}
+
+		_, err = Enqueue(ctx, jobRepository, repository.ScoreRoomJob, "score:"+payload.RoomID, job.MaxAttempts, ScoreRoomPayload{
+			RoomID:   payload.RoomID,
+			Language: payload.Language,
+		})
+		return err
+	}
+}
+
+// signFileLink gives the speech to text service a short-lived link to the
+// recordings stored by this server, other links are passed on untouched.
+func signFileLink(signer token.URLSigner, cfg config.Config, link string) string {
+	host := strings.TrimSuffix(cfg.APIHost, "/")
+	if !strings.HasPrefix(link, host+"/files/") {
+		return link
+	}
+
+	expire := cfg.SignedURLExpire
+	if expire <= 0 {
+		expire = defaultSignedURLExpire
+	}
+
+	return host + signer.SignURL(strings.TrimPrefix(link, host), time.Duration(expire)*time.Minute)
+}
+
+func TranscribeAnswerFailed(roomRepository repository.RoomRepository, attemptRepository repository.AttemptRepository) FailureHandler {
+	return func(ctx context.Context, job *repository.Job, jobErr error, dead bool) {
+		payload := TranscribeAnswerPayload{}
+		if err := decodePayload(job, &payload); err != nil {
+			return
+		}
+
+		// an attempt that doesn't count leaves the answer alone
+		if payload.AttemptID != "" {
+			attempt, err := attemptRepository.SelectOneByID(ctx, payload.AttemptID)
+			if err != nil || !attempt.Selected {
+				return
+			}
+		}
+
+		// a job waiting for its retry is back in the queue
+		status := repository.AnswerQueued
+		if dead {
+			status = repository.AnswerFailed
+		}
+
+		lastError := fmt.Sprintf("attempt %d: %s", job.Attempts, jobErr)
+		if err := roomRepository.UpdateAnswerStatus(ctx, payload.RoomID, payload.QuestionID, status, lastError); err != nil {
+			log.Printf("queue: failed to record answer status of job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// answerStatusError stops retrying a job whose answer is gone or has been
+// moved on by a newer submission.
+func answerStatusError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, repository.ErrInvalidTransition) {
+		return Permanent(err)
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrNoAttemptsLeft = errors.New("no attempts left")
+
+// AttemptRule decides which attempt of a question counts for scoring until
+// the candidate picks one.
+type AttemptRule string
+
+const (
+	AttemptLatest = AttemptRule("LATEST")
+	AttemptFirst  = AttemptRule("FIRST")
+)
+
+func AttemptRuleMapper(rule string) (AttemptRule, bool) {
+	mapper := map[string]AttemptRule{
+		"LATEST": AttemptLatest,
+		"FIRST":  AttemptFirst,
+	}
+
+	attemptRule, ok := mapper[rule]
+	return attemptRule, ok
+}
+
+type AnswerAttempt struct {
+	ID          string
+	RoomID      string
+	QuestionID  string
+	Attempt     int
+	FileLink    string
+	Transcript  sql.NullString
+	SubmittedAt time.Time
+	IsLate      bool
+	Selected    bool
+}
+
+type AttemptRepository interface {
+	Submit(context.Context, *AnswerAttempt, int, AttemptRule) error
+	Choose(context.Context, string, string, string) (*AnswerAttempt, error)
+	SelectOneByID(context.Context, string) (*AnswerAttempt, error)
+	SelectAllByQuestion(context.Context, string, string) ([]*AnswerAttempt, error)
+	UpdateTranscript(context.Context, string, string) error
+}
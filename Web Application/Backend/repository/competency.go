@@ -8,6 +8,7 @@ import (
 
 type Competency struct {
 	ID         	string
+	OrgID      	string
 	Competency 	string
 	Description string
 	Category  	string
@@ -37,10 +38,10 @@ type Levels struct {
 
 type CompetencyRepository interface {
 	Insert(context.Context, *Competency, *Levels) error
-	SelectAll(context.Context) ([]*Competency, error)
-	SelectAllCompetencyOnly(context.Context) ([]*Competency, error)
+	SelectAll(context.Context, string) ([]*Competency, error)
+	SelectAllCompetencyOnly(context.Context, string) ([]*Competency, error)
 	SelectAllByRoomID(context.Context, string) ([]*Competency, error)
-	SelectOneByID(context.Context, string) (*Competency, error)
+	SelectOneByID(context.Context, string, string) (*Competency, error)
 	Upsert(context.Context, *Competency, *Levels) error
-	DeleteByID(context.Context, string) error
+	DeleteByID(context.Context, string, string) error
 }
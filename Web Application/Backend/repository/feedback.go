@@ -26,6 +26,7 @@ func FeedbackStatusMapper(status string) (FeedbackStatus, bool) {
 
 type Feedback struct {
 	ID            	string
+	OrgID 					string
 	CompetencyID		string
 	Transcript 			string
 	Language 				string
@@ -34,11 +35,13 @@ type Feedback struct {
 	LabelFeedback		sql.NullString
 }	
 
+// FeedbackRepository lists and labels the feedback of one organization.
 type FeedbackRepository interface {
-	Insert(context.Context, []string, []string, []string, []string, string) error
-	SelectByStatus(context.Context, string) ([]*Feedback, error)
+	SelectByStatus(context.Context, string, string, string) ([]*Feedback, error)
 	UpdateFeedback(context.Context, *Feedback) error
-	UpdateBulkFeedback(context.Context, []string) error
-	IsNoDataToLabel(context.Context) (bool, error)
-	IsDataAvailable(context.Context) (bool, error)
+	// UpdateBulkFeedback queues the feedback of the organization among the
+	// ids for labeling, ids of other organizations are left alone.
+	UpdateBulkFeedback(context.Context, string, []string) error
+	IsNoDataToLabel(context.Context, string, string) (bool, error)
+	IsDataAvailable(context.Context, string, string) (bool, error)
 }
\ No newline at end of file
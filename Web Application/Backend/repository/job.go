@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobPending = JobStatus("PENDING")
+	JobRunning = JobStatus("RUNNING")
+	JobDone    = JobStatus("DONE")
+	JobDead    = JobStatus("DEAD")
+)
+
+func JobStatusMapper(status string) (JobStatus, bool) {
+	mapper := map[string]JobStatus{
+		"PENDING": JobPending,
+		"RUNNING": JobRunning,
+		"DONE":    JobDone,
+		"DEAD":    JobDead,
+	}
+
+	jobStatus, ok := mapper[status]
+	return jobStatus, ok
+}
+
+type JobType string
+
+const (
+	TranscribeAnswerJob = JobType("TRANSCRIBE_ANSWER")
+	ScoreRoomJob        = JobType("SCORE_ROOM")
+)
+
+type Job struct {
+	ID          string
+	Type        JobType
+	Payload     []byte
+	DedupeKey   sql.NullString
+	Status      JobStatus
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LockedBy    sql.NullString
+	LockedUntil sql.NullTime
+	LastError   sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   sql.NullTime
+	CompletedAt sql.NullTime
+}
+
+type JobRepository interface {
+	// Enqueue inserts a pending job and reports whether it did. A job whose
+	// dedupe key is already pending is dropped, one that is running is not.
+	Enqueue(context.Context, *Job) (bool, error)
+	// Lease locks the next runnable job of the given types for the worker
+	// until the lease expires, a job waits while another with its dedupe key
+	// runs. It returns sql.ErrNoRows when the queue is empty.
+	Lease(context.Context, string, []JobType, time.Duration) (*Job, error)
+	Complete(context.Context, *Job) error
+	// Retry puts the job back in the queue, unless a job with its dedupe key
+	// is already waiting to do the same work, then the job is done.
+	Retry(context.Context, *Job, time.Time, string) error
+	Bury(context.Context, *Job, string) error
+}
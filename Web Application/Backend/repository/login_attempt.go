@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type LoginSubject string
+
+const (
+	LoginEmail LoginSubject = "EMAIL"
+	LoginIP    LoginSubject = "IP"
+)
+
+// LoginAttempt counts the failed logins of an email or an IP address since
+// they last signed in, were unlocked or went quiet for longer than the
+// longest lockout.
+type LoginAttempt struct {
+	Subject      LoginSubject
+	Key          string
+	Failures     int
+	Lockouts     int
+	LockedUntil  sql.NullTime
+	LastFailedAt time.Time
+}
+
+type LoginAttemptRepository interface {
+	// SelectLockedUntil returns when the lock of the key ends, it is not
+	// valid when the key isn't locked.
+	SelectLockedUntil(context.Context, LoginSubject, string) (sql.NullTime, error)
+	// RecordFailure counts a failed login of the key. Every threshold
+	// failures lock the key for the base duration, doubled with each lockout
+	// before it up to the max. The end of the lock is returned when this
+	// failure locked the key.
+	RecordFailure(context.Context, LoginSubject, string, int, time.Duration, time.Duration) (sql.NullTime, error)
+	// Reset forgets the failures and the lock of the key.
+	Reset(context.Context, LoginSubject, string) error
+}
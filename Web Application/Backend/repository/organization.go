@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrOrganizationExists = errors.New("organization already exists")
+
+// Organization is a tenant of the deployment. Users, questions, competencies,
+// rooms and feedback all belong to exactly one organization.
+type Organization struct {
+	ID        string
+	Name      string
+	Deleted   bool
+	CreatedAt time.Time
+	UpdatedAt sql.NullTime
+	DeletedAt sql.NullTime
+}
+
+type OrganizationRepository interface {
+	// Insert returns ErrOrganizationExists when the name is taken.
+	Insert(context.Context, *Organization) error
+	SelectAll(context.Context) ([]*Organization, error)
+	SelectOneByID(context.Context, string) (*Organization, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PasswordReset is a forgot-password request or the invitation of a new
+// user. Only the hash of the token sent by email is stored.
+type PasswordReset struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type PasswordResetRepository interface {
+	Insert(context.Context, *PasswordReset) error
+	// Consume uses up the unexpired reset with the token hash and sets the
+	// new password of its user, along with every other pending reset of the
+	// user. It returns the user id or sql.ErrNoRows.
+	Consume(context.Context, string, string) (string, error)
+}
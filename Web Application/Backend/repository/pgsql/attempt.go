@@ -0,0 +1,290 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type attemptRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewAttemptRepository(db *sql.DB) (repository.AttemptRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(attemptQueries))
+	for key, query := range attemptQueries {
+		stmt, err := prepareStmt(db, "attemptRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Attempt Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &attemptRepository{db, ps}, nil
+}
+
+var attemptQueries = map[string]string{
+	attemptLockAnswer:          attemptLockAnswerQuery,
+	attemptCount:               attemptCountQuery,
+	attemptInsert:              attemptInsertQuery,
+	attemptUnselectAll:         attemptUnselectAllQuery,
+	attemptSelect:              attemptSelectQuery,
+	attemptQueueAnswer:         attemptQueueAnswerQuery,
+	attemptSelectOne:           attemptSelectOneQuery,
+	attemptSelectAllByQuestion: attemptSelectAllByQuestionQuery,
+	attemptUpdateTranscript:    attemptUpdateTranscriptQuery,
+}
+
+const attemptLockAnswer = "attemptLockAnswer"
+const attemptLockAnswerQuery = `SELECT
+	processing_status
+	FROM rooms_has_questions
+	WHERE room_id = $1 AND question_id = $2
+	FOR UPDATE
+`
+
+const attemptCount = "attemptCount"
+const attemptCountQuery = `SELECT
+	COUNT(*)
+	FROM answer_attempts
+	WHERE room_id = $1 AND question_id = $2
+`
+
+const attemptInsert = "attemptInsert"
+const attemptInsertQuery = `INSERT INTO
+	answer_attempts(
+		id, room_id, question_id, attempt, file_link, submitted_at, is_late, selected
+	) values(
+		$1, $2, $3, $4, $5, $6, $7, $8
+	)
+`
+
+const attemptUnselectAll = "attemptUnselectAll"
+const attemptUnselectAllQuery = `UPDATE answer_attempts
+	SET selected = false
+	WHERE room_id = $1 AND question_id = $2
+`
+
+const attemptSelect = "attemptSelect"
+const attemptSelectQuery = `UPDATE answer_attempts
+	SET selected = true
+	WHERE id = $1
+`
+
+// the transcript of the previous answer is dropped so the room doesn't look
+// answered until the new one is transcribed
+const attemptQueueAnswer = "attemptQueueAnswer"
+const attemptQueueAnswerQuery = `UPDATE rooms_has_questions SET
+	file_link = $3,
+	transcript = NULL,
+	submitted_at = $4,
+	is_late = $5,
+	processing_status = 'QUEUED',
+	processing_error = NULL,
+	queued_at = $6,
+	status_updated_at = $6
+	WHERE room_id = $1 AND question_id = $2
+`
+
+// lockAnswer locks the answer of the question for the rest of the transaction
+// and checks that it may be queued again, an answer still being processed
+// can't be replaced.
+func (r *attemptRepository) lockAnswer(ctx context.Context, tx *sql.Tx, roomId, questionId string) error {
+	var status repository.AnswerStatus
+	row := tx.StmtContext(ctx, r.ps[attemptLockAnswer]).QueryRowContext(ctx, roomId, questionId)
+	if err := row.Scan(&status); err != nil {
+		return err
+	}
+
+	if status.IsProcessing() || !status.CanTransitionTo(repository.AnswerQueued) {
+		return repository.ErrInvalidTransition
+	}
+
+	return nil
+}
+
+func (r *attemptRepository) queueAnswer(ctx context.Context, tx *sql.Tx, attempt *repository.AnswerAttempt) error {
+	_, err := tx.StmtContext(ctx, r.ps[attemptUnselectAll]).ExecContext(ctx,
+		attempt.RoomID, attempt.QuestionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[attemptQueueAnswer]).ExecContext(ctx,
+		attempt.RoomID, attempt.QuestionID, attempt.FileLink, attempt.SubmittedAt, attempt.IsLate, time.Now().UTC(),
+	)
+
+	return err
+}
+
+// Submit stores a new attempt at the question and numbers it. The attempt
+// replaces the answer that counts when the rule says so, which queues it
+// for processing.
+func (r *attemptRepository) Submit(ctx context.Context, attempt *repository.AnswerAttempt, maxAttempts int, rule repository.AttemptRule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.lockAnswer(ctx, tx, attempt.RoomID, attempt.QuestionID); err != nil {
+		return err
+	}
+
+	var count int
+	row := tx.StmtContext(ctx, r.ps[attemptCount]).QueryRowContext(ctx, attempt.RoomID, attempt.QuestionID)
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxAttempts {
+		return repository.ErrNoAttemptsLeft
+	}
+
+	attempt.Attempt = count + 1
+	attempt.Selected = rule == repository.AttemptLatest || count == 0
+	if attempt.Selected {
+		if err := r.queueAnswer(ctx, tx, attempt); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[attemptInsert]).ExecContext(ctx,
+		attempt.ID, attempt.RoomID, attempt.QuestionID, attempt.Attempt, attempt.FileLink,
+		attempt.SubmittedAt, attempt.IsLate, attempt.Selected,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Choose makes the attempt the answer that counts and queues it again.
+func (r *attemptRepository) Choose(ctx context.Context, roomId, questionId, attemptId string) (*repository.AnswerAttempt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.lockAnswer(ctx, tx, roomId, questionId); err != nil {
+		return nil, err
+	}
+
+	attempt, err := scanAttempt(tx.StmtContext(ctx, r.ps[attemptSelectOne]).QueryRowContext(ctx, attemptId))
+	if err != nil {
+		return nil, err
+	}
+	if attempt.RoomID != roomId || attempt.QuestionID != questionId {
+		return nil, sql.ErrNoRows
+	}
+
+	if err := r.queueAnswer(ctx, tx, attempt); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[attemptSelect]).ExecContext(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempt.Selected = true
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+const attemptSelectOne = "attemptSelectOne"
+const attemptSelectOneQuery = `SELECT
+	id, room_id, question_id, attempt, file_link, transcript, submitted_at, is_late, selected
+	FROM answer_attempts
+	WHERE id = $1
+`
+
+func scanAttempt(row interface{ Scan(...interface{}) error }) (*repository.AnswerAttempt, error) {
+	attempt := &repository.AnswerAttempt{}
+	err := row.Scan(&attempt.ID, &attempt.RoomID, &attempt.QuestionID, &attempt.Attempt, &attempt.FileLink,
+		&attempt.Transcript, &attempt.SubmittedAt, &attempt.IsLate, &attempt.Selected,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (r *attemptRepository) SelectOneByID(ctx context.Context, id string) (*repository.AnswerAttempt, error) {
+	return scanAttempt(r.ps[attemptSelectOne].QueryRowContext(ctx, id))
+}
+
+const attemptSelectAllByQuestion = "attemptSelectAllByQuestion"
+const attemptSelectAllByQuestionQuery = `SELECT
+	id, room_id, question_id, attempt, file_link, transcript, submitted_at, is_late, selected
+	FROM answer_attempts
+	WHERE room_id = $1 AND question_id = $2
+	ORDER BY attempt
+`
+
+func (r *attemptRepository) SelectAllByQuestion(ctx context.Context, roomId, questionId string) ([]*repository.AnswerAttempt, error) {
+	rows, err := r.ps[attemptSelectAllByQuestion].QueryContext(ctx, roomId, questionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*repository.AnswerAttempt{}
+	for rows.Next() {
+		attempt, err := scanAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+const attemptUpdateTranscript = "attemptUpdateTranscript"
+const attemptUpdateTranscriptQuery = `UPDATE answer_attempts
+	SET transcript = $2
+	WHERE id = $1
+`
+
+func (r *attemptRepository) UpdateTranscript(ctx context.Context, id, transcript string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[attemptUpdateTranscript]).ExecContext(ctx, id, transcript)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -44,9 +44,9 @@ var competencyQueries = map[string]string{
 const competencyInsert = "competencyInsert"
 const competencyInsertQuery = `INSERT INTO
 	competencies(
-		id, competency, description, category
+		id, org_id, competency, description, category
 	) values(
-		$1, $2, $3, $4
+		$1, $2, $3, $4, $5
 	)
 `
 
@@ -66,7 +66,7 @@ func (r *competencyRepository) Insert(ctx context.Context, competency *repositor
 	defer tx.Rollback()
 
 	_, err = tx.StmtContext(ctx, r.ps[competencyInsert]).ExecContext(ctx,
-		competency.ID, competency.Competency, competency.Description, competency.Category,
+		competency.ID, competency.OrgID, competency.Competency, competency.Description, competency.Category,
 	)
 	if err != nil {
 		return err
@@ -91,11 +91,11 @@ const competencySelectAllQuery = `SELECT
 	c.id, c.competency, c.description, c.category, cl.id, cl.level, cl.description, cl.competency_id
 	FROM competencies c
 	LEFT JOIN competency_levels cl ON c.id = cl.competency_id
-	WHERE c.deleted = false AND cl.deleted = false
+	WHERE c.org_id = $1 AND c.deleted = false AND cl.deleted = false
 `
 
-func (r *competencyRepository) SelectAll(ctx context.Context) ([]*repository.Competency, error) {
-	rows, err := r.ps[competencySelectAll].QueryContext(ctx)
+func (r *competencyRepository) SelectAll(ctx context.Context, orgId string) ([]*repository.Competency, error) {
+	rows, err := r.ps[competencySelectAll].QueryContext(ctx, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -131,11 +131,11 @@ const competencySelectAllCompetencyOnly = "competencySelectAllCompetencyOnly"
 const competencySelectAllCompetencyOnlyQuery = `SELECT
 	id, competency
 	FROM competencies
-	WHERE deleted = false
+	WHERE org_id = $1 AND deleted = false
 `
 
-func (r *competencyRepository) SelectAllCompetencyOnly(ctx context.Context) ([]*repository.Competency, error) {
-	rows, err := r.ps[competencySelectAllCompetencyOnly].QueryContext(ctx)
+func (r *competencyRepository) SelectAllCompetencyOnly(ctx context.Context, orgId string) ([]*repository.Competency, error) {
+	rows, err := r.ps[competencySelectAllCompetencyOnly].QueryContext(ctx, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -202,11 +202,11 @@ const competencySelectOneQuery = `SELECT
 	c.id, c.competency, c.description, c.category, cl.id, cl.level, cl.description, cl.competency_id
 	FROM competencies c
 	LEFT JOIN competency_levels cl ON c.id = cl.competency_id
-	WHERE c.deleted = false AND cl.deleted = false AND c.id = $1
+	WHERE c.org_id = $1 AND c.deleted = false AND cl.deleted = false AND c.id = $2
 `
 
-func (r *competencyRepository) SelectOneByID(ctx context.Context, id string) (*repository.Competency, error) {
-	rows, err := r.ps[competencySelectOne].QueryContext(ctx, id)
+func (r *competencyRepository) SelectOneByID(ctx context.Context, orgId, id string) (*repository.Competency, error) {
+	rows, err := r.ps[competencySelectOne].QueryContext(ctx, orgId, id)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +236,7 @@ const competencyUpdateQuery = `UPDATE competencies SET
 	description = $3,
 	category = $4,
 	updated_at = $5
-	WHERE id = $1
+	WHERE id = $1 AND org_id = $6
 `
 
 const competencyLevelUpsert = "competencyLevelUpsert"
@@ -249,6 +249,7 @@ const competencyLevelUpsertQuery = `INSERT INTO competency_levels(
 	level = excluded.level,
 	description = excluded.description,
 	updated_at = $5
+	WHERE competency_levels.competency_id = excluded.competency_id
 `
 
 const competencyLevelDeleteNotInList = "competencyLevelDeleteNotInList"
@@ -267,7 +268,7 @@ func (r *competencyRepository) Upsert(ctx context.Context, competency *repositor
 
 	currentAt := time.Now().UTC()
 	res, err := tx.StmtContext(ctx, r.ps[competencyUpdate]).ExecContext(ctx,
-		competency.ID, competency.Competency, competency.Description, competency.Category, currentAt,
+		competency.ID, competency.Competency, competency.Description, competency.Category, currentAt, competency.OrgID,
 	)
 	if err != nil {
 		return err
@@ -288,6 +289,15 @@ func (r *competencyRepository) Upsert(ctx context.Context, competency *repositor
 		return err
 	}
 
+	// a level id of another competency is left alone
+	updatedRows, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != int64(len(levels.IDs)) {
+		return sql.ErrNoRows
+	}
+
 	res, err = tx.StmtContext(ctx, r.ps[competencyLevelDeleteNotInList]).ExecContext(ctx,
 		competency.ID, levels.IDs, currentAt,
 	)
@@ -306,7 +316,7 @@ const competencyDelete = "competencyDelete"
 const competencyDeleteQuery = `UPDATE competencies SET
 	deleted = true,
 	deleted_at = $2
-	WHERE id = $1
+	WHERE id = $1 AND org_id = $3
 `
 
 const competencyLevelDelete = "competencyLevelDelete"
@@ -316,7 +326,7 @@ const competencyLevelDeleteQuery = `UPDATE competency_levels SET
 	WHERE competency_id = $1
 `
 
-func (r *competencyRepository) DeleteByID(ctx context.Context, id string) error {
+func (r *competencyRepository) DeleteByID(ctx context.Context, orgId, id string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -324,7 +334,7 @@ func (r *competencyRepository) DeleteByID(ctx context.Context, id string) error
 	defer tx.Rollback()
 
 	deletedAt := time.Now().UTC()
-	res, err := tx.StmtContext(ctx, r.ps[competencyDelete]).ExecContext(ctx, id, deletedAt)
+	res, err := tx.StmtContext(ctx, r.ps[competencyDelete]).ExecContext(ctx, id, deletedAt, orgId)
 	if err != nil {
 		return err
 	}
@@ -27,7 +27,6 @@ func NewFeedbackRepository(db *sql.DB) (repository.FeedbackRepository, error) {
 }
 
 var feedbackQueries = map[string]string{
-	feedbackInsert: 				feedbackInsertQuery,
 	feedbackSelectByStatus: feedbackSelectByStatusQuery,
 	feedbackUpdate: 				feedbackUpdateQuery,
 	feedbackUpdateBulk: 		feedbackUpdateBulkQuery,
@@ -35,42 +34,15 @@ var feedbackQueries = map[string]string{
 	feedbackIsDataAvailable: feedbackIsDataAvailableQuery,
 }
 
-const feedbackInsert = "feedbackInsert"
-const feedbackInsertQuery = `INSERT INTO
-	"feedback_results"(
-		id, transcript, competency_id, status, label_result, language
-	) values(
-		UNNEST($1::uuid[]), UNNEST($2::text[]), UNNEST($3::uuid[]), $4, UNNEST($5::uuid[]), $6
-	)
-`
-
-func (r *feedbackRepository) Insert(ctx context.Context, feedbackID []string, transcript []string, cID []string, resID []string, language string) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	status := "UNLABELED"
-	_, err = tx.StmtContext(ctx, r.ps[feedbackInsert]).ExecContext(ctx,
-		feedbackID, transcript, cID, status, resID, language,
-	)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
-}
-
 const feedbackSelectByStatus = "feedbackSelectByStatus"
 const feedbackSelectByStatusQuery = `SELECT
 	id, competency_id, transcript, status, label_result, label_feedback
 	FROM "feedback_results"
-	WHERE status = $1
+	WHERE org_id = $1 AND status = $2 AND language = $3
 `
 
-func (r *feedbackRepository) SelectByStatus(ctx context.Context, status string) ([]*repository.Feedback, error) {
-	rows, err := r.ps[feedbackSelectByStatus].QueryContext(ctx, status)
+func (r *feedbackRepository) SelectByStatus(ctx context.Context, orgId, status, language string) ([]*repository.Feedback, error) {
+	rows, err := r.ps[feedbackSelectByStatus].QueryContext(ctx, orgId, status, language)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +67,7 @@ func (r *feedbackRepository) SelectByStatus(ctx context.Context, status string)
 const feedbackUpdate = "feedbackUpdate"
 const feedbackUpdateQuery = `UPDATE "feedback_results"
 	SET status = $1, label_feedback = $2, transcript = $3
-	WHERE id = $4
+	WHERE id = $4 AND org_id = $5
 `
 
 func (r *feedbackRepository) UpdateFeedback(ctx context.Context, feedback *repository.Feedback) error {
@@ -105,21 +77,29 @@ func (r *feedbackRepository) UpdateFeedback(ctx context.Context, feedback *repos
 	}
 	defer tx.Rollback()
 
-	_, err = tx.StmtContext(ctx, r.ps[feedbackUpdate]).ExecContext(ctx, feedback.Status, feedback.LabelFeedback, feedback.Transcript, feedback.ID)
+	res, err := tx.StmtContext(ctx, r.ps[feedbackUpdate]).ExecContext(ctx, feedback.Status, feedback.LabelFeedback, feedback.Transcript, feedback.ID, feedback.OrgID)
 	if err != nil {
 		return err
 	}
 
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
 	return tx.Commit()
 }
 
 const feedbackUpdateBulk = "feedbackUpdateBulk"
 const feedbackUpdateBulkQuery = `UPDATE "feedback_results"
 	SET status = $1
-	WHERE id = ANY($2::uuid[])
+	WHERE org_id = $3 AND id = ANY($2::uuid[])
 `
 
-func (r *feedbackRepository) UpdateBulkFeedback(ctx context.Context, ids []string) error {
+func (r *feedbackRepository) UpdateBulkFeedback(ctx context.Context, orgId string, ids []string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -127,7 +107,10 @@ func (r *feedbackRepository) UpdateBulkFeedback(ctx context.Context, ids []strin
 	defer tx.Rollback()
 
 	status := "TO_LABEL"
-	_, err = tx.StmtContext(ctx, r.ps[feedbackUpdateBulk]).ExecContext(ctx, status, ids)
+	_, err = tx.StmtContext(ctx, r.ps[feedbackUpdateBulk]).ExecContext(ctx, status, ids, orgId)
+	if err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
@@ -135,12 +118,12 @@ func (r *feedbackRepository) UpdateBulkFeedback(ctx context.Context, ids []strin
 const feedbackIsNoDataToLabel = "feedbackIsNoDataToLabel"
 const feedbackIsNoDataToLabelQuery = `SELECT COUNT(*) = 0
 	FROM "feedback_results"
-	WHERE status = 'TO_LABEL' AND language = 'ENGLISH'
+	WHERE org_id = $1 AND status = 'TO_LABEL' AND language = $2
 `
 
-func (r *feedbackRepository) IsNoDataToLabel(ctx context.Context) (bool, error) {
+func (r *feedbackRepository) IsNoDataToLabel(ctx context.Context, orgId, language string) (bool, error) {
 	var isNoData bool
-	err := r.db.QueryRowContext(ctx, feedbackIsNoDataToLabelQuery).Scan(&isNoData)
+	err := r.ps[feedbackIsNoDataToLabel].QueryRowContext(ctx, orgId, language).Scan(&isNoData)
 	if err != nil {
 		return false, err
 	}
@@ -151,12 +134,12 @@ func (r *feedbackRepository) IsNoDataToLabel(ctx context.Context) (bool, error)
 const feedbackIsDataAvailable = "feedbackIsDataAvailable"
 const feedbackIsDataAvailableQuery = `SELECT COUNT(*) > 0
 	FROM "feedback_results"
-	WHERE status = 'UNLABELED' AND language = 'INDONESIAN'
+	WHERE org_id = $1 AND status = 'UNLABELED' AND language = $2
 `
 
-func (r *feedbackRepository) IsDataAvailable(ctx context.Context) (bool, error) {
+func (r *feedbackRepository) IsDataAvailable(ctx context.Context, orgId, language string) (bool, error) {
 	var isData bool
-	err := r.db.QueryRowContext(ctx, feedbackIsDataAvailableQuery).Scan(&isData)
+	err := r.ps[feedbackIsDataAvailable].QueryRowContext(ctx, orgId, language).Scan(&isData)
 	if err != nil {
 		return false, err
 	}
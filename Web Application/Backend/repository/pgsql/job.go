@@ -0,0 +1,196 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type jobRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewJobRepository(db *sql.DB) (repository.JobRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(jobQueries))
+	for key, query := range jobQueries {
+		stmt, err := prepareStmt(db, "jobRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Job Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &jobRepository{db, ps}, nil
+}
+
+var jobQueries = map[string]string{
+	jobInsert:   jobInsertQuery,
+	jobLease:    jobLeaseQuery,
+	jobComplete: jobCompleteQuery,
+	jobRetry:    jobRetryQuery,
+	jobBury:     jobBuryQuery,
+}
+
+const jobInsert = "jobInsert"
+const jobInsertQuery = `INSERT INTO
+	jobs(
+		id, type, payload, dedupe_key, status, max_attempts, run_at
+	) values(
+		$1, $2, $3, $4, $5, $6, $7
+	)
+	ON CONFLICT (dedupe_key) WHERE status = 'PENDING' DO NOTHING
+`
+
+func (r *jobRepository) Enqueue(ctx context.Context, job *repository.Job) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[jobInsert]).ExecContext(ctx,
+		job.ID, job.Type, job.Payload, job.DedupeKey, repository.JobPending, job.MaxAttempts, job.RunAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	insertedRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return insertedRows == 1, nil
+}
+
+// A running job whose lease has expired belongs to a worker that died or
+// was restarted, so it is picked up again like a pending one. A pending job
+// waits for the running job with its dedupe key, so the last one to run
+// sees the latest input.
+const jobLease = "jobLease"
+const jobLeaseQuery = `UPDATE jobs SET
+	status = 'RUNNING',
+	attempts = attempts + 1,
+	locked_by = $1,
+	locked_until = $2,
+	updated_at = $3
+	WHERE id = (
+		SELECT id FROM jobs j
+		WHERE type = ANY($4::TEXT[]) AND (
+			(status = 'PENDING' AND run_at <= $3 AND NOT EXISTS(
+				SELECT 1 FROM jobs r
+				WHERE r.dedupe_key = j.dedupe_key AND r.status = 'RUNNING' AND r.locked_until >= $3
+			)) OR
+			(status = 'RUNNING' AND locked_until < $3)
+		)
+		ORDER BY run_at
+		FOR UPDATE SKIP LOCKED
+		LIMIT 1
+	)
+	RETURNING id, type, payload, dedupe_key, status, attempts, max_attempts, run_at, locked_by, locked_until, last_error, created_at
+`
+
+func (r *jobRepository) Lease(ctx context.Context, workerID string, types []repository.JobType, lease time.Duration) (*repository.Job, error) {
+	now := time.Now().UTC()
+
+	jobTypes := make([]string, 0, len(types))
+	for _, t := range types {
+		jobTypes = append(jobTypes, string(t))
+	}
+
+	job := &repository.Job{}
+	row := r.ps[jobLease].QueryRowContext(ctx, workerID, now.Add(lease), now, jobTypes)
+	err := row.Scan(&job.ID, &job.Type, &job.Payload, &job.DedupeKey, &job.Status, &job.Attempts,
+		&job.MaxAttempts, &job.RunAt, &job.LockedBy, &job.LockedUntil, &job.LastError, &job.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+const jobComplete = "jobComplete"
+const jobCompleteQuery = `UPDATE jobs SET
+	status = 'DONE',
+	locked_by = NULL,
+	locked_until = NULL,
+	updated_at = $3,
+	completed_at = $3
+	WHERE id = $1 AND locked_by = $2
+`
+
+func (r *jobRepository) Complete(ctx context.Context, job *repository.Job) error {
+	return r.execOwned(ctx, jobComplete, job.ID, job.LockedBy.String, time.Now().UTC())
+}
+
+// A job queued while this one ran already does the same work on newer
+// input, so this one is done instead of waiting next to it.
+const jobRetry = "jobRetry"
+const jobRetryQuery = `UPDATE jobs SET
+	status = CASE WHEN queued.id IS NULL THEN 'PENDING' ELSE 'DONE' END,
+	run_at = $3,
+	last_error = $4,
+	locked_by = NULL,
+	locked_until = NULL,
+	updated_at = $5,
+	completed_at = CASE WHEN queued.id IS NULL THEN NULL ELSE $5 END
+	FROM jobs j
+	LEFT JOIN jobs queued ON queued.dedupe_key = j.dedupe_key AND queued.status = 'PENDING'
+	WHERE jobs.id = $1 AND jobs.locked_by = $2 AND j.id = jobs.id
+`
+
+func (r *jobRepository) Retry(ctx context.Context, job *repository.Job, runAt time.Time, lastError string) error {
+	return r.execOwned(ctx, jobRetry, job.ID, job.LockedBy.String, runAt, lastError, time.Now().UTC())
+}
+
+const jobBury = "jobBury"
+const jobBuryQuery = `UPDATE jobs SET
+	status = 'DEAD',
+	last_error = $3,
+	locked_by = NULL,
+	locked_until = NULL,
+	updated_at = $4
+	WHERE id = $1 AND locked_by = $2
+`
+
+func (r *jobRepository) Bury(ctx context.Context, job *repository.Job, lastError string) error {
+	return r.execOwned(ctx, jobBury, job.ID, job.LockedBy.String, lastError, time.Now().UTC())
+}
+
+// execOwned runs an update that only applies while the worker still holds
+// the lease. It returns sql.ErrNoRows when the lease was lost to another worker.
+func (r *jobRepository) execOwned(ctx context.Context, key string, args ...interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[key]).ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
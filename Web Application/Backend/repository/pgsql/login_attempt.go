@@ -0,0 +1,154 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type loginAttemptRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewLoginAttemptRepository(db *sql.DB) (repository.LoginAttemptRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(loginAttemptQueries))
+	for key, query := range loginAttemptQueries {
+		stmt, err := prepareStmt(db, "loginAttemptRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Login Attempt Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &loginAttemptRepository{db, ps}, nil
+}
+
+var loginAttemptQueries = map[string]string{
+	loginAttemptSelectLockedUntil: loginAttemptSelectLockedUntilQuery,
+	loginAttemptUpsertFailure:     loginAttemptUpsertFailureQuery,
+	loginAttemptLock:              loginAttemptLockQuery,
+	loginAttemptDelete:            loginAttemptDeleteQuery,
+}
+
+const loginAttemptSelectLockedUntil = "loginAttemptSelectLockedUntil"
+const loginAttemptSelectLockedUntilQuery = `SELECT locked_until
+	FROM login_attempts WHERE subject = $1 AND key = $2 AND locked_until > $3
+`
+
+func (r *loginAttemptRepository) SelectLockedUntil(ctx context.Context, subject repository.LoginSubject, key string) (sql.NullTime, error) {
+	var lockedUntil sql.NullTime
+
+	row := r.ps[loginAttemptSelectLockedUntil].QueryRowContext(ctx, subject, key, time.Now().UTC())
+	if err := row.Scan(&lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullTime{}, nil
+		}
+
+		return sql.NullTime{}, err
+	}
+
+	return lockedUntil, nil
+}
+
+// a key quiet since before $4 starts counting again from scratch
+const loginAttemptUpsertFailure = "loginAttemptUpsertFailure"
+const loginAttemptUpsertFailureQuery = `INSERT INTO
+	login_attempts(
+		subject, key, failures, lockouts, last_failed_at
+	) values(
+		$1, $2, 1, 0, $3
+	)
+	ON CONFLICT (subject, key) DO UPDATE SET
+		failures = CASE WHEN login_attempts.last_failed_at < $4 THEN 1 ELSE login_attempts.failures + 1 END,
+		lockouts = CASE WHEN login_attempts.last_failed_at < $4 THEN 0 ELSE login_attempts.lockouts END,
+		last_failed_at = $3
+	RETURNING failures, lockouts
+`
+
+const loginAttemptLock = "loginAttemptLock"
+const loginAttemptLockQuery = `UPDATE login_attempts SET
+	failures = 0,
+	lockouts = lockouts + 1,
+	locked_until = $3
+	WHERE subject = $1 AND key = $2
+`
+
+func (r *loginAttemptRepository) RecordFailure(
+	ctx context.Context,
+	subject repository.LoginSubject,
+	key string,
+	threshold int,
+	baseLock, maxLock time.Duration,
+) (sql.NullTime, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	var failures, lockouts int
+	row := tx.StmtContext(ctx, r.ps[loginAttemptUpsertFailure]).QueryRowContext(ctx, subject, key, now, now.Add(-maxLock))
+	if err := row.Scan(&failures, &lockouts); err != nil {
+		return sql.NullTime{}, err
+	}
+
+	lockedUntil := sql.NullTime{}
+	if failures >= threshold {
+		lockFor := baseLock
+		for i := 0; i < lockouts && lockFor < maxLock; i++ {
+			lockFor *= 2
+		}
+		if lockFor > maxLock {
+			lockFor = maxLock
+		}
+		lockedUntil = sql.NullTime{Time: now.Add(lockFor), Valid: true}
+
+		res, err := tx.StmtContext(ctx, r.ps[loginAttemptLock]).ExecContext(ctx, subject, key, lockedUntil)
+		if err != nil {
+			return sql.NullTime{}, err
+		}
+
+		updatedRows, err := res.RowsAffected()
+		if err != nil {
+			return sql.NullTime{}, err
+		}
+		if updatedRows != 1 {
+			return sql.NullTime{}, sql.ErrNoRows
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return sql.NullTime{}, err
+	}
+
+	return lockedUntil, nil
+}
+
+const loginAttemptDelete = "loginAttemptDelete"
+const loginAttemptDeleteQuery = `DELETE FROM login_attempts WHERE subject = $1 AND key = $2`
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, subject repository.LoginSubject, key string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[loginAttemptDelete]).ExecContext(ctx, subject, key)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+)
+
+type organizationRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewOrganizationRepository(db *sql.DB) (repository.OrganizationRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(organizationQueries))
+	for key, query := range organizationQueries {
+		stmt, err := prepareStmt(db, "organizationRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Organization Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &organizationRepository{db, ps}, nil
+}
+
+var organizationQueries = map[string]string{
+	organizationInsert:        organizationInsertQuery,
+	organizationSelectAll:     organizationSelectAllQuery,
+	organizationSelectOneByID: organizationSelectOneByIDQuery,
+}
+
+const organizationInsert = "organizationInsert"
+const organizationInsertQuery = `INSERT INTO
+	organizations(
+		id, name
+	) values(
+		$1, $2
+	)
+	ON CONFLICT (name) DO NOTHING
+`
+
+func (r *organizationRepository) Insert(ctx context.Context, org *repository.Organization) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[organizationInsert]).ExecContext(ctx, org.ID, org.Name)
+	if err != nil {
+		return err
+	}
+
+	insertedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if insertedRows != 1 {
+		return repository.ErrOrganizationExists
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const organizationSelectAll = "organizationSelectAll"
+const organizationSelectAllQuery = `SELECT id, name, created_at
+	FROM organizations
+	WHERE deleted = false
+	ORDER BY name
+`
+
+func (r *organizationRepository) SelectAll(ctx context.Context) ([]*repository.Organization, error) {
+	rows, err := r.ps[organizationSelectAll].QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := make([]*repository.Organization, 0)
+	for rows.Next() {
+		org := &repository.Organization{}
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		orgs = append(orgs, org)
+	}
+
+	return orgs, nil
+}
+
+const organizationSelectOneByID = "organizationSelectOneByID"
+const organizationSelectOneByIDQuery = `SELECT id, name, created_at
+	FROM organizations
+	WHERE id = $1 AND deleted = false
+`
+
+func (r *organizationRepository) SelectOneByID(ctx context.Context, id string) (*repository.Organization, error) {
+	org := &repository.Organization{}
+
+	row := r.ps[organizationSelectOneByID].QueryRowContext(ctx, id)
+	if err := row.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
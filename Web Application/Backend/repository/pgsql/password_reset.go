@@ -0,0 +1,127 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type passwordResetRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewPasswordResetRepository(db *sql.DB) (repository.PasswordResetRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(passwordResetQueries))
+	for key, query := range passwordResetQueries {
+		stmt, err := prepareStmt(db, "passwordResetRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Password Reset Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &passwordResetRepository{db, ps}, nil
+}
+
+var passwordResetQueries = map[string]string{
+	passwordResetInsert:         passwordResetInsertQuery,
+	passwordResetConsume:        passwordResetConsumeQuery,
+	passwordResetUseAllByUserID: passwordResetUseAllByUserIDQuery,
+	passwordResetUpdatePassword: passwordResetUpdatePasswordQuery,
+}
+
+const passwordResetInsert = "passwordResetInsert"
+const passwordResetInsertQuery = `INSERT INTO
+	password_resets(
+		id, user_id, token_hash, expires_at
+	) values(
+		$1, $2, $3, $4
+	)
+`
+
+func (r *passwordResetRepository) Insert(ctx context.Context, reset *repository.PasswordReset) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[passwordResetInsert]).ExecContext(ctx,
+		reset.ID, reset.UserID, reset.TokenHash, reset.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const passwordResetConsume = "passwordResetConsume"
+const passwordResetConsumeQuery = `UPDATE password_resets SET
+	used_at = $2
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+	RETURNING user_id
+`
+
+const passwordResetUseAllByUserID = "passwordResetUseAllByUserID"
+const passwordResetUseAllByUserIDQuery = `UPDATE password_resets SET
+	used_at = $2
+	WHERE user_id = $1 AND used_at IS NULL
+`
+
+// an invited user proves they own the email by following the invitation
+const passwordResetUpdatePassword = "passwordResetUpdatePassword"
+const passwordResetUpdatePasswordQuery = `UPDATE "users" SET
+	password = $2,
+	status = CASE WHEN status = 'INVITED' THEN 'VERIFIED' ELSE status END,
+	updated_at = $3
+	WHERE id = $1
+`
+
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash, password string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	var userId string
+	row := tx.StmtContext(ctx, r.ps[passwordResetConsume]).QueryRowContext(ctx, tokenHash, now)
+	if err := row.Scan(&userId); err != nil {
+		return "", err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[passwordResetUseAllByUserID]).ExecContext(ctx, userId, now)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := tx.StmtContext(ctx, r.ps[passwordResetUpdatePassword]).ExecContext(ctx, userId, password, now)
+	if err != nil {
+		return "", err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if updatedRows != 1 {
+		return "", sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return userId, nil
+}
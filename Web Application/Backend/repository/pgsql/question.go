@@ -43,9 +43,9 @@ var questionQueries = map[string]string{
 const questionInsert = "questionInsert"
 const questionInsertQuery = `INSERT INTO
 	questions(
-		id, question, duration_limit, org_position
+		id, org_id, question, duration_limit, org_position
 	) values(
-		$1, $2, $3, $4
+		$1, $2, $3, $4, $5
 	)
 `
 const questionLabelInsert = "questionLabelInsert"
@@ -53,7 +53,10 @@ const questionLabelInsertQuery = `INSERT INTO
 	questions_labels(
 		id, question_id, competency_id
 	) SELECT
-	UNNEST($1::UUID[]), $2, UNNEST($3::UUID[])
+	l.id, $2, l.competency_id
+	FROM UNNEST($1::UUID[], $3::UUID[]) AS l(id, competency_id)
+	INNER JOIN competencies c ON c.id = l.competency_id
+	WHERE c.org_id = $4
 `
 
 func (r *questionRepository) Insert(ctx context.Context, question *repository.Question, labels *repository.Labels) error {
@@ -64,19 +67,27 @@ func (r *questionRepository) Insert(ctx context.Context, question *repository.Qu
 	defer tx.Rollback()
 
 	_, err = tx.StmtContext(ctx, r.ps[questionInsert]).ExecContext(ctx,
-		question.ID, question.Question, question.DurationLimit, question.OrgPosition,
+		question.ID, question.OrgID, question.Question, question.DurationLimit, question.OrgPosition,
 	)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.StmtContext(ctx, r.ps[questionLabelInsert]).ExecContext(ctx,
-		labels.IDs, question.ID, labels.CompetencyIDs,
+	res, err := tx.StmtContext(ctx, r.ps[questionLabelInsert]).ExecContext(ctx,
+		labels.IDs, question.ID, labels.CompetencyIDs, question.OrgID,
 	)
 	if err != nil {
 		return err
 	}
 
+	insertedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if insertedRows != int64(len(labels.IDs)) {
+		return sql.ErrNoRows
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -89,11 +100,11 @@ const questionSelectAllQuery = `SELECT
 	q.id, q.question, q.duration_limit, q.org_position, ql.id, ql.competency_id, ql.question_id
 	FROM questions q
 	LEFT JOIN questions_labels ql ON q.id = ql.question_id
-	WHERE q.deleted = false AND ql.deleted = false
+	WHERE q.org_id = $1 AND q.deleted = false AND ql.deleted = false
 `
 
-func (r *questionRepository) SelectAll(ctx context.Context) ([]*repository.Question, error) {
-	rows, err := r.ps[questionSelectAll].QueryContext(ctx)
+func (r *questionRepository) SelectAll(ctx context.Context, orgId string) ([]*repository.Question, error) {
+	rows, err := r.ps[questionSelectAll].QueryContext(ctx, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +144,7 @@ const questionSelectAllByRoomIDQuery = `SELECT
 	INNER JOIN rooms_has_questions rq ON q.id = rq.question_id
 	LEFT JOIN questions_labels ql ON q.id = ql.question_id
 	WHERE q.deleted = false AND rq.room_id = $1 AND ql.deleted = false
+	ORDER BY rq.position, q.id
 `
 
 func (r *questionRepository) SelectAllByRoomID(ctx context.Context, id string) ([]*repository.Question, error) {
@@ -174,11 +186,11 @@ const questionSelectOneQuery = `SELECT
 	q.id, q.question, q.duration_limit, q.org_position, ql.id, ql.competency_id, ql.question_id
 	FROM questions q
 	LEFT JOIN questions_labels ql ON q.id = ql.question_id
-	WHERE q.deleted = false AND ql.deleted = false AND q.id = $1
+	WHERE q.org_id = $1 AND q.deleted = false AND ql.deleted = false AND q.id = $2
 `
 
-func (r *questionRepository) SelectOneByID(ctx context.Context, id string) (*repository.Question, error) {
-	rows, err := r.ps[questionSelectOne].QueryContext(ctx, id)
+func (r *questionRepository) SelectOneByID(ctx context.Context, orgId, id string) (*repository.Question, error) {
+	rows, err := r.ps[questionSelectOne].QueryContext(ctx, orgId, id)
 	if err != nil {
 		return nil, err
 	}
@@ -209,17 +221,21 @@ const questionUpdateQuery = `UPDATE questions SET
 	duration_limit = $3,
 	org_position = $4,
 	updated_at = $5
-	WHERE id = $1
+	WHERE id = $1 AND org_id = $6
 `
 
 const questionLabelUpsert = "questionLabelUpsert"
 const questionLabelUpsertQuery = `INSERT INTO questions_labels(
 		id, question_id, competency_id
 	) SELECT
-	UNNEST($1::UUID[]), $2, UNNEST($3::UUID[])
+	l.id, $2, l.competency_id
+	FROM UNNEST($1::UUID[], $3::UUID[]) AS l(id, competency_id)
+	INNER JOIN competencies c ON c.id = l.competency_id
+	WHERE c.org_id = $5
 	ON CONFLICT (id)
 	DO UPDATE SET
 	updated_at = $4
+	WHERE questions_labels.question_id = excluded.question_id
 `
 
 const questionLabelDeleteNotInList = "questionLabelDeleteNotInList"
@@ -238,7 +254,7 @@ func (r *questionRepository) Upsert(ctx context.Context, question *repository.Qu
 
 	updatedAt := time.Now().UTC()
 	res, err := tx.StmtContext(ctx, r.ps[questionUpdate]).ExecContext(ctx,
-		question.ID, question.Question, question.DurationLimit, question.OrgPosition, updatedAt,
+		question.ID, question.Question, question.DurationLimit, question.OrgPosition, updatedAt, question.OrgID,
 	)
 	if err != nil {
 		return err
@@ -253,12 +269,22 @@ func (r *questionRepository) Upsert(ctx context.Context, question *repository.Qu
 	}
 
 	res, err = tx.StmtContext(ctx, r.ps[questionLabelUpsert]).ExecContext(ctx,
-		labels.IDs, question.ID, labels.CompetencyIDs, updatedAt,
+		labels.IDs, question.ID, labels.CompetencyIDs, updatedAt, question.OrgID,
 	)
 	if err != nil {
 		return err
 	}
 
+	// labels of another question or competencies of another organization
+	// are left out
+	updatedRows, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != int64(len(labels.IDs)) {
+		return sql.ErrNoRows
+	}
+
 	res, err = tx.StmtContext(ctx, r.ps[questionLabelDeleteNotInList]).ExecContext(ctx,
 		question.ID, labels.IDs, updatedAt,
 	)
@@ -277,7 +303,7 @@ const questionDelete = "questionDelete"
 const questionDeleteQuery = `UPDATE questions SET
 	deleted = true,
 	deleted_at = $2
-	WHERE id = $1
+	WHERE id = $1 AND org_id = $3
 `
 
 const questionLabelDelete = "questionLabelDelete"
@@ -287,7 +313,7 @@ const questionLabelDeleteQuery = `UPDATE questions_labels SET
 	WHERE question_id = $1
 `
 
-func (r *questionRepository) DeleteByID(ctx context.Context, id string) error {
+func (r *questionRepository) DeleteByID(ctx context.Context, orgId, id string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -295,7 +321,7 @@ func (r *questionRepository) DeleteByID(ctx context.Context, id string) error {
 	defer tx.Rollback()
 
 	deletedAt := time.Now().UTC()
-	res, err := tx.StmtContext(ctx, r.ps[questionDelete]).ExecContext(ctx, id, deletedAt)
+	res, err := tx.StmtContext(ctx, r.ps[questionDelete]).ExecContext(ctx, id, deletedAt, orgId)
 	if err != nil {
 		return err
 	}
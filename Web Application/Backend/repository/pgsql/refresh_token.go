@@ -0,0 +1,128 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type refreshTokenRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewRefreshTokenRepository(db *sql.DB) (repository.RefreshTokenRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(refreshTokenQueries))
+	for key, query := range refreshTokenQueries {
+		stmt, err := prepareStmt(db, "refreshTokenRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Refresh Token Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &refreshTokenRepository{db, ps}, nil
+}
+
+var refreshTokenQueries = map[string]string{
+	refreshTokenInsert:        refreshTokenInsertQuery,
+	refreshTokenSelectOneByID: refreshTokenSelectOneByIDQuery,
+	refreshTokenRevoke:        refreshTokenRevokeQuery,
+	refreshTokenExtendSession: refreshTokenExtendSessionQuery,
+}
+
+const refreshTokenInsert = "refreshTokenInsert"
+const refreshTokenInsertQuery = `INSERT INTO
+	refresh_tokens(
+		id, user_id, session_id, expires_at
+	) values(
+		$1, $2, $3, $4
+	)
+`
+
+const refreshTokenSelectOneByID = "refreshTokenSelectOneByID"
+const refreshTokenSelectOneByIDQuery = `SELECT
+	id, user_id, session_id, expires_at, revoked_at, replaced_by, created_at
+	FROM refresh_tokens
+	WHERE id = $1
+`
+
+func (r *refreshTokenRepository) SelectOneByID(ctx context.Context, id string) (*repository.RefreshToken, error) {
+	token := &repository.RefreshToken{}
+	row := r.ps[refreshTokenSelectOneByID].QueryRowContext(ctx, id)
+	err := row.Scan(&token.ID, &token.UserID, &token.SessionID, &token.ExpiresAt,
+		&token.RevokedAt, &token.ReplacedBy, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+const refreshTokenRevoke = "refreshTokenRevoke"
+const refreshTokenRevokeQuery = `UPDATE refresh_tokens SET
+	revoked_at = $2,
+	replaced_by = $3
+	WHERE id = $1 AND revoked_at IS NULL
+`
+
+const refreshTokenExtendSession = "refreshTokenExtendSession"
+const refreshTokenExtendSessionQuery = `UPDATE sessions SET
+	expires_at = $2,
+	last_seen_at = $3
+	WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (r *refreshTokenRepository) Rotate(ctx context.Context, id string, next *repository.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	// only one of two requests racing with the same token gets to rotate it
+	res, err := tx.StmtContext(ctx, r.ps[refreshTokenRevoke]).ExecContext(ctx, id, now, next.ID)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return repository.ErrTokenReused
+	}
+
+	res, err = tx.StmtContext(ctx, r.ps[refreshTokenExtendSession]).ExecContext(ctx, next.SessionID, next.ExpiresAt, now)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return repository.ErrTokenReused
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[refreshTokenInsert]).ExecContext(ctx,
+		next.ID, next.UserID, next.SessionID, next.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
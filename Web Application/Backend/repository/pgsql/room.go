@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"interview/summarization/repository"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type roomRepository struct {
@@ -38,30 +40,53 @@ var roomQueries = map[string]string{
 	roomSelectAllByRoomGroupID:				  roomSelectAllByRoomGroupIDQuery,
 	roomGroupSelectOneByID:				      roomGroupSelectOneByIDQuery,
 	roomSelectOneByIDUserID:				    roomSelectOneByIDUserIDQuery,
+	roomSelectOwnersByID:								roomSelectOwnersByIDQuery,
+	roomGroupSelectOwnersByID:					roomGroupSelectOwnersByIDQuery,
 	roomInsertTranscript:				        roomInsertTranscriptQuery,
 	roomIsAnswered:				              roomIsAnsweredQuery,
 	roomGetAnswers:				              roomGetAnswersQuery,
+	roomInsertResultSet:								roomInsertResultSetQuery,
 	roomInsertResult:				            roomInsertResultQuery,
+	roomInsertFeedback:				          roomInsertFeedbackQuery,
+	roomIsScoredByJob:									roomIsScoredByJobQuery,
+	roomSelectLatestResultSet:					roomSelectLatestResultSetQuery,
+	roomSelectResultSets:								roomSelectResultSetsQuery,
 	roomUpdateStatus:				            roomUpdateStatusQuery,
+	roomExpire:													roomExpireQuery,
+	roomSetStatus:											roomSetStatusQuery,
+	roomExists:													roomExistsQuery,
+	roomSelectExpiring:									roomSelectExpiringQuery,
+	roomStatusEventInsert:							roomStatusEventInsertQuery,
+	roomStatusEventSelectAll:						roomStatusEventSelectAllQuery,
+	roomSelectMilestonesByGroupID:			roomSelectMilestonesByGroupIDQuery,
 	roomUpdateQuestionRoom:							roomUpdateQuestionRoomQuery,
 	roomUpdateQuestionCond:							roomUpdateQuestionCondQuery,
+	roomUpdateAnswerStatus:							roomUpdateAnswerStatusQuery,
+	roomAnswerExists:										roomAnswerExistsQuery,
+	roomUpdateRoomAnswersStatus:				roomUpdateRoomAnswersStatusQuery,
+	roomSelectAnswerProcessing:					roomSelectAnswerProcessingQuery,
 	roomGetResultCompetencies:				  roomGetResultCompetenciesQuery,
 	roomGetResultQuestions:				      roomGetResultQuestionsQuery,
 	roomGetQuestionDetail:							roomGetQuestionDetailQuery,
+	roomSelectQuestionsInRoom:					roomSelectQuestionsInRoomQuery,
+	roomReorderQuestions:								roomReorderQuestionsQuery,
 	roomReview:				                  roomReviewQuery,
 	roomDeleteByID:											roomDeleteByIDQuery,
 	roomGroupDeleteByID:								roomGroupDeleteByIDQuery,
 	roomCompetenciesDelete:							roomCompetenciesDeleteQuery,
 	roomQuestionsDelete:								roomQuestionsDeleteQuery,
+	roomAttemptsDelete:								roomAttemptsDeleteQuery,
 	roomResultCompetenciesDelete:				roomResultCompetenciesDeleteQuery,
+	roomResultSetsDelete:								roomResultSetsDeleteQuery,
 }
 
 const roomInsert = "roomInsert"
 const roomInsertQuery = `INSERT INTO
 	rooms(
-		id, title, description, "start", "end", status, language, preparation_time, interviewer_id, room_group_id
+		id, title, description, "start", "end", status, language, preparation_time, interviewer_id, room_group_id, shuffle_questions,
+		max_attempts, attempt_rule, org_id
 	) values(
-		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 	)
 	RETURNING id
 `
@@ -69,9 +94,12 @@ const roomInsertQuery = `INSERT INTO
 const roomQuestionsInsert = "roomQuestionsInsert"
 const roomQuestionsInsertQuery = `INSERT INTO
 	rooms_has_questions(
-		room_id, question_id
+		room_id, question_id, position
 	) SELECT
-		$1, UNNEST($2::UUID[])
+		$1, q.id, q.position - 1
+	FROM UNNEST($2::UUID[]) WITH ORDINALITY AS q(id, position)
+	INNER JOIN questions qs ON qs.id = q.id
+	WHERE qs.org_id = $3
 `
 
 const roomCompetenciesInsert = "roomCompetenciesInsert"
@@ -79,13 +107,17 @@ const roomCompetenciesInsertQuery = `INSERT INTO
 	rooms_has_competencies(
 		room_id, competency_id
 	) SELECT
-		$1, UNNEST($2::UUID[])
+		$1, c.id
+	FROM UNNEST($2::UUID[]) AS c(id)
+	INNER JOIN competencies cs ON cs.id = c.id
+	WHERE cs.org_id = $3
 `
 
 func (r *roomRepository) Insert(
 	ctx context.Context,
 	room *repository.Room,
-	questions, competencies []string) error {
+	questions, competencies []string,
+	actorId string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -95,24 +127,20 @@ func (r *roomRepository) Insert(
 	var id string
 	row := tx.StmtContext(ctx, r.ps[roomInsert]).QueryRowContext(ctx,
 		room.ID, room.Title, room.Description, room.Start, room.End,
-		room.Status, room.Language, room.PrepationTime, room.InterviewerID, room.RoomGroupID,
+		room.Status, room.Language, room.PrepationTime, room.InterviewerID, room.RoomGroupID, room.ShuffleQuestions,
+		room.MaxAttempts, room.AttemptRule, room.OrgID,
 	)
 	err = row.Scan(&id)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.StmtContext(ctx, r.ps[roomQuestionsInsert]).ExecContext(ctx,
-		room.ID, questions,
-	)
+	err = r.insertStatusEvent(ctx, tx, room.ID, sql.NullString{}, room.Status, actorId, "")
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.StmtContext(ctx, r.ps[roomCompetenciesInsert]).ExecContext(ctx,
-		room.ID, competencies,
-	)
-	if err != nil {
+	if err := r.insertQuestionsAndCompetencies(ctx, tx, room.OrgID, room.ID, questions, competencies); err != nil {
 		return err
 	}
 
@@ -126,10 +154,11 @@ func (r *roomRepository) Insert(
 
 func (r *roomRepository) UpdateQuestionsAndCompetenciesRoom(
 	ctx context.Context,
-	roomId string,
+	orgId, roomId string,
 	questions []string,
 	competencies []string,
-	status repository.RoomStatus) error {
+	status repository.RoomStatus,
+	actorId string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -138,31 +167,69 @@ func (r *roomRepository) UpdateQuestionsAndCompetenciesRoom(
 
 	// update status
 	var submission *string
-	_, err = tx.StmtContext(ctx, r.ps[roomUpdateStatus]).ExecContext(ctx,
-		roomId, status, submission,
+	var fromStatus string
+	row := tx.StmtContext(ctx, r.ps[roomUpdateStatus]).QueryRowContext(ctx,
+		roomId, status, submission, repository.RoomStatusSources(status),
 	)
+	if err := row.Scan(&fromStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return r.transitionError(ctx, tx, roomId)
+		}
+		return err
+	}
 
+	err = r.insertStatusEvent(ctx, tx, roomId, sql.NullString{String: fromStatus, Valid: true}, status, actorId, "")
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.StmtContext(ctx, r.ps[roomQuestionsInsert]).ExecContext(ctx,
-		roomId, questions,
+	if err := r.insertQuestionsAndCompetencies(ctx, tx, orgId, roomId, questions, competencies); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// insertQuestionsAndCompetencies links the questions and competencies to the
+// room, any of them from another organization fails with sql.ErrNoRows.
+func (r *roomRepository) insertQuestionsAndCompetencies(
+	ctx context.Context,
+	tx *sql.Tx,
+	orgId, roomId string,
+	questions, competencies []string) error {
+	res, err := tx.StmtContext(ctx, r.ps[roomQuestionsInsert]).ExecContext(ctx,
+		roomId, questions, orgId,
 	)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.StmtContext(ctx, r.ps[roomCompetenciesInsert]).ExecContext(ctx,
-		roomId, competencies,
+	insertedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if insertedRows != int64(len(questions)) {
+		return sql.ErrNoRows
+	}
+
+	res, err = tx.StmtContext(ctx, r.ps[roomCompetenciesInsert]).ExecContext(ctx,
+		roomId, competencies, orgId,
 	)
 	if err != nil {
 		return err
 	}
 
-	if err = tx.Commit(); err != nil {
+	insertedRows, err = res.RowsAffected()
+	if err != nil {
 		return err
 	}
+	if insertedRows != int64(len(competencies)) {
+		return sql.ErrNoRows
+	}
 
 	return nil
 }
@@ -170,9 +237,9 @@ func (r *roomRepository) UpdateQuestionsAndCompetenciesRoom(
 const roomInsertRoomGroup = "roomInsertRoomGroup"
 const roomInsertRoomGroupQuery = `INSERT INTO
 	room_groups(
-		id, title, org_position, interviewee_id
+		id, title, org_position, interviewee_id, org_id
 	) values(
-		$1, $2, $3, $4
+		$1, $2, $3, $4, $5
 	)
 `
 
@@ -186,7 +253,7 @@ func (r *roomRepository) InsertRoomGroup(
 	defer tx.Rollback()
 
 	_, err = tx.StmtContext(ctx, r.ps[roomInsertRoomGroup]).ExecContext(ctx,
-		roomGroup.ID, roomGroup.Title, roomGroup.OrgPosition, roomGroup.IntervieweeID,
+		roomGroup.ID, roomGroup.Title, roomGroup.OrgPosition, roomGroup.IntervieweeID, roomGroup.OrgID,
 	)
 
 	if err != nil {
@@ -205,10 +272,11 @@ const roomGroupSelectAllQuery = `SELECT
 	rg.id, rg.title, rg.org_position, u.email, u.name
 	FROM room_groups rg
 	INNER JOIN "users" u ON rg.interviewee_id = u.id
+	WHERE rg.org_id = $1
 `
 
-func (r *roomRepository) SelectAllRoomGroup(ctx context.Context) ([]*repository.RoomGroup, error) {
-	rows, err := r.ps[roomGroupSelectAll].QueryContext(ctx)
+func (r *roomRepository) SelectAllRoomGroup(ctx context.Context, orgId string) ([]*repository.RoomGroup, error) {
+	rows, err := r.ps[roomGroupSelectAll].QueryContext(ctx, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +305,7 @@ const roomGroupSelectAllByInterviewerID = "roomGroupSelectAllByInterviewerID"
 const roomGroupSelectAllByInterviewerIDQuery = `WITH distinct_room_groups AS (
 	SELECT DISTINCT room_group_id
 	FROM rooms
-	WHERE interviewer_id = $1 AND deleted = false
+	WHERE org_id = $1 AND interviewer_id = $2 AND deleted = false
 	)
 	SELECT
 		rg.id, rg.title, rg.org_position, u.email, u.name
@@ -246,8 +314,8 @@ const roomGroupSelectAllByInterviewerIDQuery = `WITH distinct_room_groups AS (
 	INNER JOIN "users" u ON rg.interviewee_id = u.id
 `
 
-func (r *roomRepository) SelectAllRoomGroupByInterviewerID(ctx context.Context, id string) ([]*repository.RoomGroup, error) {
-	rows, err := r.ps[roomGroupSelectAllByInterviewerID].QueryContext(ctx, id)
+func (r *roomRepository) SelectAllRoomGroupByInterviewerID(ctx context.Context, orgId, id string) ([]*repository.RoomGroup, error) {
+	rows, err := r.ps[roomGroupSelectAllByInterviewerID].QueryContext(ctx, orgId, id)
 	if err != nil {
 		return nil, err
 	}
@@ -276,11 +344,11 @@ const roomGroupSelectAllByIntervieweeIDQuery = `SELECT
 	rg.id, rg.title, rg.org_position, u.email, u.name
 	FROM room_groups rg
 	INNER JOIN "users" u ON rg.interviewee_id = u.id
-	WHERE rg.interviewee_id = $1 AND rg.deleted = false
+	WHERE rg.org_id = $1 AND rg.interviewee_id = $2 AND rg.deleted = false
 `
 
-func (r *roomRepository) SelectAllRoomGroupByIntervieweeID(ctx context.Context, id string) ([]*repository.RoomGroup, error) {
-	rows, err := r.ps[roomGroupSelectAllByIntervieweeID].QueryContext(ctx, id)
+func (r *roomRepository) SelectAllRoomGroupByIntervieweeID(ctx context.Context, orgId, id string) ([]*repository.RoomGroup, error) {
+	rows, err := r.ps[roomGroupSelectAllByIntervieweeID].QueryContext(ctx, orgId, id)
 	if err != nil {
 		return nil, err
 	}
@@ -309,11 +377,11 @@ const roomSelectAllByRoomGroupIDQuery = `SELECT
 	r.id, r.title, r.description, r."start", r."end", r.submission, r.status, r.note, r.language, r.preparation_time, u.name
 	FROM rooms r
 	INNER JOIN users u ON r.interviewer_id = u.id
-	WHERE r.room_group_id = $1 AND r.deleted = false
+	WHERE r.org_id = $1 AND r.room_group_id = $2 AND r.deleted = false
 `
 
-func (r *roomRepository) SelectAllRoomByGroupID(ctx context.Context, id string) ([]*repository.Room, error) {
-	rows, err := r.ps[roomSelectAllByRoomGroupID].QueryContext(ctx, id)
+func (r *roomRepository) SelectAllRoomByGroupID(ctx context.Context, orgId, id string) ([]*repository.Room, error) {
+	rows, err := r.ps[roomSelectAllByRoomGroupID].QueryContext(ctx, orgId, id)
 	if err != nil {
 		return nil, err
 	}
@@ -344,15 +412,15 @@ const roomGroupSelectOneByIDQuery = `SELECT
 	rg.id, rg.title, rg.org_position, u.email, u.name, u.phone
 	FROM room_groups rg
 	INNER JOIN "users" u ON rg.interviewee_id = u.id
-	WHERE rg.id = $1 AND rg.deleted = false
+	WHERE rg.org_id = $1 AND rg.id = $2 AND rg.deleted = false
 `
 
-func (r *roomRepository) SelectRoomGroupByID(ctx context.Context, id string) (*repository.RoomGroup, error) {
+func (r *roomRepository) SelectRoomGroupByID(ctx context.Context, orgId, id string) (*repository.RoomGroup, error) {
 	roomGroup := &repository.RoomGroup{
 		Interviewee: &repository.User{},
 	}
 
-	row := r.ps[roomGroupSelectOneByID].QueryRowContext(ctx, id)
+	row := r.ps[roomGroupSelectOneByID].QueryRowContext(ctx, orgId, id)
 	err := row.Scan(&roomGroup.ID, &roomGroup.Title, &roomGroup.OrgPosition,
 		&roomGroup.Interviewee.Email, &roomGroup.Interviewee.Name, &roomGroup.Interviewee.Phone,
 	)
@@ -366,21 +434,21 @@ func (r *roomRepository) SelectRoomGroupByID(ctx context.Context, id string) (*r
 const roomSelectOneByIDUserID = "roomSelectOneByIDUserID"
 const roomSelectOneByIDUserIDQuery = `SELECT 
 	r.id, r.title, r.description, r."start", r."end", r.is_started, r.current_question, r.submission, r.status, r.note, r.language, r.preparation_time, r.room_group_id,
-	u.name, u.email
+	r.shuffle_questions, r.max_attempts, r.attempt_rule, u.name, u.email
 	FROM rooms r
 	INNER JOIN "users" u ON r.interviewer_id = u.id
-	WHERE r.id = $1 AND r.deleted = false
+	WHERE r.org_id = $1 AND r.id = $2 AND r.deleted = false
 `
 
-func (r *roomRepository) SelectOneRoomByID(ctx context.Context, id string) (*repository.Room, error) {
+func (r *roomRepository) SelectOneRoomByID(ctx context.Context, orgId, id string) (*repository.Room, error) {
 	room := &repository.Room{
 		Interviewer: &repository.User{},
 	}
 
-	row := r.ps[roomSelectOneByIDUserID].QueryRowContext(ctx, id)
+	row := r.ps[roomSelectOneByIDUserID].QueryRowContext(ctx, orgId, id)
 	err := row.Scan(&room.ID, &room.Title, &room.Description, &room.Start, &room.End,
 		&room.IsStarted, &room.CurrQuestion, &room.Submission, &room.Status, &room.Note, &room.Language, &room.PrepationTime, &room.RoomGroupID,
-		&room.Interviewer.Name, &room.Interviewer.Email,
+		&room.ShuffleQuestions, &room.MaxAttempts, &room.AttemptRule, &room.Interviewer.Name, &room.Interviewer.Email,
 	)
 	if err != nil {
 		return nil, err
@@ -389,6 +457,63 @@ func (r *roomRepository) SelectOneRoomByID(ctx context.Context, id string) (*rep
 	return room, err
 }
 
+const roomSelectOwnersByID = "roomSelectOwnersByID"
+const roomSelectOwnersByIDQuery = `SELECT
+	r.id, r.interviewer_id, rg.interviewee_id
+	FROM rooms r
+	LEFT JOIN room_groups rg ON r.room_group_id = rg.id
+	WHERE r.org_id = $1 AND r.id = $2 AND r.deleted = false
+`
+
+func (r *roomRepository) SelectOwnersByID(ctx context.Context, orgId, roomId string) (*repository.RoomOwners, error) {
+	owners := &repository.RoomOwners{}
+	row := r.ps[roomSelectOwnersByID].QueryRowContext(ctx, orgId, roomId)
+	if err := row.Scan(&owners.RoomID, &owners.InterviewerID, &owners.IntervieweeID); err != nil {
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+const roomGroupSelectOwnersByID = "roomGroupSelectOwnersByID"
+const roomGroupSelectOwnersByIDQuery = `SELECT
+	rg.id, rg.interviewee_id, r.interviewer_id
+	FROM room_groups rg
+	LEFT JOIN rooms r ON r.room_group_id = rg.id AND r.deleted = false
+	WHERE rg.org_id = $1 AND rg.id = $2 AND rg.deleted = false
+`
+
+func (r *roomRepository) SelectGroupOwnersByID(ctx context.Context, orgId, roomGroupId string) (*repository.RoomGroupOwners, error) {
+	rows, err := r.ps[roomGroupSelectOwnersByID].QueryContext(ctx, orgId, roomGroupId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var owners *repository.RoomGroupOwners
+	for rows.Next() {
+		if owners == nil {
+			owners = &repository.RoomGroupOwners{InterviewerIDs: []string{}}
+		}
+
+		var interviewerId sql.NullString
+		if err := rows.Scan(&owners.RoomGroupID, &owners.IntervieweeID, &interviewerId); err != nil {
+			return nil, err
+		}
+		if interviewerId.Valid {
+			owners.InterviewerIDs = append(owners.InterviewerIDs, interviewerId.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if owners == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return owners, nil
+}
+
 const roomInsertTranscript = "roomInsertTranscript"
 const roomInsertTranscriptQuery = `UPDATE rooms_has_questions SET
 	file_link = $3,
@@ -480,35 +605,264 @@ func (r *roomRepository) GetAnswers(ctx context.Context, roomId string) (string,
 	return answers, nil
 }
 
+const roomInsertResultSet = "roomInsertResultSet"
+const roomInsertResultSetQuery = `INSERT INTO
+	result_sets(
+		id, room_id, job_id, model_version, language, transcript_hash, created_at
+	) values(
+		$1, $2, $3, $4, $5, $6, $7
+	)
+`
+
 const roomInsertResult = "roomInsertResult"
 const roomInsertResultQuery = `INSERT INTO
 	results_competencies(
-		room_id, competency, level, result
+		result_set_id, room_id, competency, level, result
 	) SELECT
-	$1, UNNEST($2::TEXT[]), UNNEST($3::TEXT[]), UNNEST($4::REAL[])
+	$1, $2, UNNEST($3::TEXT[]), UNNEST($4::TEXT[]), UNNEST($5::REAL[])
+`
+
+const roomInsertFeedback = "roomInsertFeedback"
+const roomInsertFeedbackQuery = `INSERT INTO
+	"feedback_results"(
+		id, org_id, transcript, competency_id, status, label_result, language
+	) SELECT
+		f.id, c.org_id, f.transcript, f.competency_id, 'UNLABELED', f.label_result, $5
+	FROM UNNEST($1::uuid[], $2::text[], $3::uuid[], $4::uuid[]) AS f(id, transcript, competency_id, label_result)
+	INNER JOIN competencies c ON c.id = f.competency_id
+`
+
+const roomIsScoredByJob = "roomIsScoredByJob"
+const roomIsScoredByJobQuery = `SELECT EXISTS(
+	SELECT 1 FROM result_sets WHERE job_id = $1
+)
+`
+
+func (r *roomRepository) IsScoredByJob(ctx context.Context, jobId string) (bool, error) {
+	var isScored bool
+	err := r.ps[roomIsScoredByJob].QueryRowContext(ctx, jobId).Scan(&isScored)
+	if err != nil {
+		return false, err
+	}
+
+	return isScored, nil
+}
+
+const roomSelectLatestResultSet = "roomSelectLatestResultSet"
+const roomSelectLatestResultSetQuery = `SELECT
+	id, room_id, job_id, model_version, language, transcript_hash, created_at
+	FROM result_sets
+	WHERE room_id = $1
+	ORDER BY created_at DESC
+	LIMIT 1
+`
+
+func (r *roomRepository) SelectLatestResultSet(ctx context.Context, roomId string) (*repository.ResultSet, error) {
+	resultSet := &repository.ResultSet{}
+
+	row := r.ps[roomSelectLatestResultSet].QueryRowContext(ctx, roomId)
+	err := row.Scan(&resultSet.ID, &resultSet.RoomID, &resultSet.JobID, &resultSet.ModelVersion,
+		&resultSet.Language, &resultSet.TranscriptHash, &resultSet.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultSet, nil
+}
+
+const roomSelectResultSets = "roomSelectResultSets"
+const roomSelectResultSetsQuery = `SELECT
+	rs.id, rs.room_id, rs.job_id, rs.model_version, rs.language, rs.transcript_hash, rs.created_at,
+	rc.competency, rc.level, rc.result
+	FROM result_sets rs
+	INNER JOIN results_competencies rc ON rc.result_set_id = rs.id
+	WHERE rs.room_id = $1
+	ORDER BY rs.created_at DESC, rs.id
 `
 
+// SelectResultSets returns every scoring run of the room, newest first.
+func (r *roomRepository) SelectResultSets(ctx context.Context, roomId string) ([]*repository.ResultSet, error) {
+	rows, err := r.ps[roomSelectResultSets].QueryContext(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resultSets := []*repository.ResultSet{}
+	for rows.Next() {
+		resultSet := &repository.ResultSet{}
+		var competency string
+		var level string
+		var val float64
+		err := rows.Scan(&resultSet.ID, &resultSet.RoomID, &resultSet.JobID, &resultSet.ModelVersion,
+			&resultSet.Language, &resultSet.TranscriptHash, &resultSet.CreatedAt,
+			&competency, &level, &val,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		lenRS := len(resultSets)
+		if lenRS > 0 && resultSets[lenRS-1].ID == resultSet.ID {
+			resultSet = resultSets[lenRS-1]
+		} else {
+			resultSet.Results = repository.ResultCompetency{}
+			resultSets = append(resultSets, resultSet)
+		}
+
+		if _, ok := resultSet.Results[competency]; !ok {
+			resultSet.Results[competency] = map[string]float64{}
+		}
+		resultSet.Results[competency][level] = val
+	}
+
+	return resultSets, nil
+}
+
 const roomUpdateStatus = "roomUpdateStatus"
-const roomUpdateStatusQuery = `UPDATE rooms
+const roomUpdateStatusQuery = `WITH prev AS (
+	SELECT status FROM rooms WHERE id = $1 FOR UPDATE
+)
+UPDATE rooms
 	SET status = $2,
 	submission = $3
-	WHERE id = $1
+	WHERE id = $1 AND status = ANY($4::TEXT[])
+	RETURNING (SELECT status FROM prev)
+`
+
+const roomSetStatus = "roomSetStatus"
+const roomSetStatusQuery = `WITH prev AS (
+	SELECT status FROM rooms WHERE id = $1 FOR UPDATE
+)
+UPDATE rooms
+	SET status = $2,
+	updated_at = $3
+	WHERE id = $1 AND deleted = false AND status = ANY($4::TEXT[])
+	RETURNING (SELECT status FROM prev)
+`
+
+const roomStatusEventInsert = "roomStatusEventInsert"
+const roomStatusEventInsertQuery = `INSERT INTO
+	room_status_events(
+		id, room_id, actor_id, from_status, to_status, note, created_at
+	) values(
+		$1, $2, $3, $4, $5, $6, $7
+	)
+`
+
+// insertStatusEvent records a status change of the room inside the
+// transaction that made it. Staying in the same status is not recorded.
+func (r *roomRepository) insertStatusEvent(
+	ctx context.Context,
+	tx *sql.Tx,
+	roomId string,
+	from sql.NullString,
+	to repository.RoomStatus,
+	actorId, note string,
+) error {
+	if from.Valid && from.String == string(to) {
+		return nil
+	}
+
+	_, err := tx.StmtContext(ctx, r.ps[roomStatusEventInsert]).ExecContext(ctx,
+		uuid.NewString(), roomId, sql.NullString{String: actorId, Valid: actorId != ""},
+		from, to, sql.NullString{String: note, Valid: note != ""}, time.Now().UTC(),
+	)
+
+	return err
+}
+
+const roomExists = "roomExists"
+const roomExistsQuery = `SELECT EXISTS(
+	SELECT 1 FROM rooms WHERE id = $1 AND deleted = false
+)
 `
 
-func (r *roomRepository) InsertResult(ctx context.Context, roomId string, competency, level []string, result []float64) error {
+// transitionError explains why a status update did not touch the room: it is
+// either gone or its current status cannot move to the requested one.
+func (r *roomRepository) transitionError(ctx context.Context, tx *sql.Tx, roomId string) error {
+	var exists bool
+	row := tx.StmtContext(ctx, r.ps[roomExists]).QueryRowContext(ctx, roomId)
+	if err := row.Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	return repository.ErrInvalidTransition
+}
+
+func (r *roomRepository) UpdateStatus(ctx context.Context, roomId string, status repository.RoomStatus, actorId string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var fromStatus string
+	row := tx.StmtContext(ctx, r.ps[roomSetStatus]).QueryRowContext(ctx,
+		roomId, status, time.Now().UTC(), repository.RoomStatusSources(status),
+	)
+	if err := row.Scan(&fromStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return r.transitionError(ctx, tx, roomId)
+		}
+		return err
+	}
+
+	err = r.insertStatusEvent(ctx, tx, roomId, sql.NullString{String: fromStatus, Valid: true}, status, actorId, "")
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *roomRepository) InsertResult(ctx context.Context, resultSet *repository.ResultSet, competency, level []string, result []float64) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	_, err = tx.StmtContext(ctx, r.ps[roomInsertResultSet]).ExecContext(ctx,
+		resultSet.ID, resultSet.RoomID, resultSet.JobID, resultSet.ModelVersion,
+		resultSet.Language, resultSet.TranscriptHash, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.StmtContext(ctx, r.ps[roomInsertResult]).ExecContext(ctx,
-		roomId, competency, level, result,
+		resultSet.ID, resultSet.RoomID, competency, level, result,
 	)
 	if err != nil {
 		return err
 	}
 
+	if len(resultSet.Feedback) > 0 {
+		var ids, transcripts, competencyIds, labelResults []string
+		for _, feedback := range resultSet.Feedback {
+			ids = append(ids, feedback.ID)
+			transcripts = append(transcripts, feedback.Transcript)
+			competencyIds = append(competencyIds, feedback.CompetencyID)
+			labelResults = append(labelResults, feedback.LabelResult)
+		}
+
+		_, err = tx.StmtContext(ctx, r.ps[roomInsertFeedback]).ExecContext(ctx,
+			ids, transcripts, competencyIds, labelResults, resultSet.Language,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -516,7 +870,80 @@ func (r *roomRepository) InsertResult(ctx context.Context, roomId string, compet
 	return nil
 }
 
-func (r *roomRepository) UpdateStatusAndSubmission(ctx context.Context, room *repository.Room) error {
+const roomSelectExpiring = "roomSelectExpiring"
+const roomSelectExpiringQuery = `SELECT
+	id, status
+	FROM rooms
+	WHERE status = ANY($2::TEXT[]) AND "end" < $1 AND deleted = false
+	FOR UPDATE SKIP LOCKED
+`
+
+const roomExpire = "roomExpire"
+const roomExpireQuery = `UPDATE rooms
+	SET status = 'EXPIRED',
+	updated_at = $2
+	WHERE id = ANY($1::UUID[])
+`
+
+// ExpireRooms closes the rooms that ended before the given time without being
+// submitted. Answers given so far are left untouched.
+func (r *roomRepository) ExpireRooms(ctx context.Context, endedBefore time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.StmtContext(ctx, r.ps[roomSelectExpiring]).QueryContext(ctx,
+		endedBefore, repository.RoomStatusSources(repository.Expired),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	roomIds := []string{}
+	fromStatuses := []string{}
+	for rows.Next() {
+		var roomId, fromStatus string
+		if err := rows.Scan(&roomId, &fromStatus); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		roomIds = append(roomIds, roomId)
+		fromStatuses = append(fromStatuses, fromStatus)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(roomIds) == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[roomExpire]).ExecContext(ctx,
+		roomIds, time.Now().UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, roomId := range roomIds {
+		err = r.insertStatusEvent(ctx, tx, roomId, sql.NullString{String: fromStatuses[i], Valid: true}, repository.Expired, "", "")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int64(len(roomIds)), nil
+}
+
+func (r *roomRepository) UpdateStatusAndSubmission(ctx context.Context, room *repository.Room, actorId string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -524,9 +951,18 @@ func (r *roomRepository) UpdateStatusAndSubmission(ctx context.Context, room *re
 	defer tx.Rollback()
 
 	submission := time.Now().UTC()
-	_, err = tx.StmtContext(ctx, r.ps[roomUpdateStatus]).ExecContext(ctx,
-		room.ID, room.Status, submission,
+	var fromStatus string
+	row := tx.StmtContext(ctx, r.ps[roomUpdateStatus]).QueryRowContext(ctx,
+		room.ID, room.Status, submission, repository.RoomStatusSources(room.Status),
 	)
+	if err := row.Scan(&fromStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return r.transitionError(ctx, tx, room.ID)
+		}
+		return err
+	}
+
+	err = r.insertStatusEvent(ctx, tx, room.ID, sql.NullString{String: fromStatus, Valid: true}, room.Status, actorId, "")
 	if err != nil {
 		return err
 	}
@@ -540,24 +976,34 @@ func (r *roomRepository) UpdateStatusAndSubmission(ctx context.Context, room *re
 
 const roomUpdateQuestionRoom = "roomUpdateQuetionRoom"
 const roomUpdateQuestionRoomQuery = `UPDATE rooms_has_questions
-SET start_answer = $3
+SET start_answer = COALESCE(start_answer, $3)
 WHERE room_id = $1 AND question_id = $2
 `
 
-func (r *roomRepository) UpdateQuestionByRoomID(ctx context.Context, roomId, questionId, startAnswer string) error {
+// UpdateQuestionByRoomID stamps the start of an answer, the first stamp is
+// kept so reloading the question does not restart its clock.
+func (r *roomRepository) UpdateQuestionByRoomID(ctx context.Context, roomId, questionId string, startAnswer time.Time) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.StmtContext(ctx, r.ps[roomUpdateQuestionRoom]).ExecContext(ctx,
+	res, err := tx.StmtContext(ctx, r.ps[roomUpdateQuestionRoom]).ExecContext(ctx,
 		roomId, questionId, startAnswer,
 	)
 	if err != nil {
 		return err
 	}
 
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -592,11 +1038,143 @@ func (r *roomRepository) UpdateRoomQuestionCond(ctx context.Context, roomId stri
 	return nil
 }
 
+const roomUpdateAnswerStatus = "roomUpdateAnswerStatus"
+const roomUpdateAnswerStatusQuery = `UPDATE rooms_has_questions SET
+	processing_status = $3,
+	processing_error = $4,
+	queued_at = CASE WHEN $3 = 'QUEUED' THEN $5 ELSE queued_at END,
+	transcribed_at = CASE WHEN $3 = 'TRANSCRIBED' THEN $5 ELSE transcribed_at END,
+	scored_at = CASE WHEN $3 = 'SCORED' THEN $5 ELSE scored_at END,
+	failed_at = CASE WHEN $3 = 'FAILED' THEN $5 ELSE failed_at END,
+	status_updated_at = $5
+	WHERE room_id = $1 AND question_id = $2 AND processing_status = ANY($6::TEXT[])
+`
+
+func (r *roomRepository) UpdateAnswerStatus(ctx context.Context, roomId, questionId string, status repository.AnswerStatus, lastError string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[roomUpdateAnswerStatus]).ExecContext(ctx,
+		roomId, questionId, status, sql.NullString{String: lastError, Valid: lastError != ""},
+		time.Now().UTC(), repository.AnswerStatusSources(status),
+	)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		var exists bool
+		row := tx.StmtContext(ctx, r.ps[roomAnswerExists]).QueryRowContext(ctx, roomId, questionId)
+		if err := row.Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return sql.ErrNoRows
+		}
+
+		return repository.ErrInvalidTransition
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const roomAnswerExists = "roomAnswerExists"
+const roomAnswerExistsQuery = `SELECT EXISTS(
+	SELECT 1 FROM rooms_has_questions WHERE room_id = $1 AND question_id = $2
+)
+`
+
+const roomUpdateRoomAnswersStatus = "roomUpdateRoomAnswersStatus"
+const roomUpdateRoomAnswersStatusQuery = `UPDATE rooms_has_questions SET
+	processing_status = $2,
+	processing_error = $3,
+	queued_at = CASE WHEN $2 = 'QUEUED' THEN $4 ELSE queued_at END,
+	transcribed_at = CASE WHEN $2 = 'TRANSCRIBED' THEN $4 ELSE transcribed_at END,
+	scored_at = CASE WHEN $2 = 'SCORED' THEN $4 ELSE scored_at END,
+	failed_at = CASE WHEN $2 = 'FAILED' THEN $4 ELSE failed_at END,
+	status_updated_at = $4
+	WHERE room_id = $1 AND processing_status = ANY($5::TEXT[])
+`
+
+// UpdateRoomAnswersStatus moves every answer of the room that is allowed to
+// make the transition, answers in any other state are left untouched.
+func (r *roomRepository) UpdateRoomAnswersStatus(ctx context.Context, roomId string, status repository.AnswerStatus, lastError string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[roomUpdateRoomAnswersStatus]).ExecContext(ctx,
+		roomId, status, sql.NullString{String: lastError, Valid: lastError != ""},
+		time.Now().UTC(), repository.AnswerStatusSources(status),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const roomSelectAnswerProcessing = "roomSelectAnswerProcessing"
+const roomSelectAnswerProcessingQuery = `SELECT
+	rq.question_id, q.question, rq.processing_status, rq.processing_error,
+	rq.queued_at, rq.transcribed_at, rq.scored_at, rq.failed_at, rq.status_updated_at,
+	rq.submitted_at, rq.is_late
+	FROM rooms_has_questions rq
+	INNER JOIN questions q ON rq.question_id = q.id
+	WHERE rq.room_id = $1
+`
+
+func (r *roomRepository) SelectAnswerProcessing(ctx context.Context, roomId string) ([]*repository.AnswerProcessing, error) {
+	rows, err := r.ps[roomSelectAnswerProcessing].QueryContext(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []*repository.AnswerProcessing{}
+	for rows.Next() {
+		answer := &repository.AnswerProcessing{}
+		err := rows.Scan(&answer.QuestionID, &answer.Question, &answer.Status, &answer.LastError,
+			&answer.QueuedAt, &answer.TranscribedAt, &answer.ScoredAt, &answer.FailedAt, &answer.StatusUpdatedAt,
+			&answer.SubmittedAt, &answer.IsLate,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		answers = append(answers, answer)
+	}
+
+	return answers, nil
+}
+
 const roomGetResultCompetencies = "roomGetResultCompetencies"
 const roomGetResultCompetenciesQuery = `SELECT
-	competency, level, result
-	FROM results_competencies
-	WHERE room_id = $1
+	rc.competency, rc.level, rc.result
+	FROM results_competencies rc
+	WHERE rc.result_set_id = (
+		SELECT id FROM result_sets
+		WHERE room_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	)
 `
 
 func (r *roomRepository) GetResultCompetencies(ctx context.Context, roomId string) (repository.ResultCompetency, error) {
@@ -666,17 +1244,89 @@ func (r *roomRepository) GetResultQuestions(ctx context.Context, roomId string)
 
 const roomGetQuestionDetail = "roomGetQuestionDetail"
 const roomGetQuestionDetailQuery = `SELECT
-	rq.question_id, q.question, q.duration_limit, rq.start_answer
+	rq.question_id, q.question, q.duration_limit, rq.start_answer, rq.processing_status, rq.submitted_at
 	FROM rooms_has_questions rq
 	INNER JOIN questions q ON rq.question_id = q.id
 	WHERE rq.room_id = $1 AND rq.question_id = $2
 `
 
+const roomSelectQuestionsInRoom = "roomSelectQuestionsInRoom"
+const roomSelectQuestionsInRoomQuery = `SELECT
+	rq.question_id, q.question, q.duration_limit, rq.start_answer, rq.processing_status, rq.submitted_at
+	FROM rooms_has_questions rq
+	INNER JOIN questions q ON rq.question_id = q.id
+	WHERE rq.room_id = $1
+	ORDER BY rq.position, rq.question_id
+`
+
+func (r *roomRepository) SelectQuestionsInRoom(ctx context.Context, roomId string) ([]*repository.QuestionInRoom, error) {
+	rows, err := r.ps[roomSelectQuestionsInRoom].QueryContext(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []*repository.QuestionInRoom{}
+	for rows.Next() {
+		question := &repository.QuestionInRoom{}
+		err := rows.Scan(&question.ID, &question.Question, &question.DurationLimit, &question.StartAnswer,
+			&question.AnswerStatus, &question.SubmittedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+const roomReorderQuestions = "roomReorderQuestions"
+const roomReorderQuestionsQuery = `UPDATE rooms_has_questions rq
+	SET position = q.position - 1
+	FROM UNNEST($2::UUID[]) WITH ORDINALITY AS q(id, position)
+	WHERE rq.room_id = $1 AND rq.question_id = q.id
+`
+
+// ReorderQuestions puts the questions of the room in the given order, which
+// has to list every question of the room exactly once.
+func (r *roomRepository) ReorderQuestions(ctx context.Context, roomId string, questions []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[roomReorderQuestions]).ExecContext(ctx,
+		roomId, questions,
+	)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != int64(len(questions)) {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *roomRepository) GetOneQuestionByRoomID(ctx context.Context, roomId, questionId string) (*repository.QuestionInRoom, error) {
 	question := &repository.QuestionInRoom{}
 
 	row := r.ps[roomGetQuestionDetail].QueryRowContext(ctx, roomId, questionId)
-	err := row.Scan(&question.ID, &question.Question, &question.DurationLimit, &question.StartAnswer)
+	err := row.Scan(&question.ID, &question.Question, &question.DurationLimit, &question.StartAnswer,
+		&question.AnswerStatus, &question.SubmittedAt,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -686,33 +1336,38 @@ func (r *roomRepository) GetOneQuestionByRoomID(ctx context.Context, roomId, que
 
 
 const roomReview = "roomReview"
-const roomReviewQuery = `UPDATE rooms SET
+const roomReviewQuery = `WITH prev AS (
+	SELECT status FROM rooms WHERE id = $1 FOR UPDATE
+)
+UPDATE rooms SET
 	status = $2,
 	note = $3
-	WHERE id = $1
+	WHERE id = $1 AND status = ANY($4::TEXT[])
+	RETURNING (SELECT status FROM prev)
 `
 
-func (r *roomRepository) Review(ctx context.Context, room *repository.Room) error {
+func (r *roomRepository) Review(ctx context.Context, room *repository.Room, actorId string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	res, err := tx.StmtContext(ctx, r.ps[roomReview]).ExecContext(ctx,
-		room.ID, room.Status, room.Note.String,
+	var fromStatus string
+	row := tx.StmtContext(ctx, r.ps[roomReview]).QueryRowContext(ctx,
+		room.ID, room.Status, room.Note.String, repository.RoomStatusSources(room.Status),
 	)
-	if err != nil {
+	if err := row.Scan(&fromStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return r.transitionError(ctx, tx, room.ID)
+		}
 		return err
 	}
 
-	updatedRows, err := res.RowsAffected()
+	err = r.insertStatusEvent(ctx, tx, room.ID, sql.NullString{String: fromStatus, Valid: true}, room.Status, actorId, room.Note.String)
 	if err != nil {
 		return err
 	}
-	if updatedRows != 1 {
-		return sql.ErrNoRows
-	}
 
 	if err = tx.Commit(); err != nil {
 		return err
@@ -721,11 +1376,92 @@ func (r *roomRepository) Review(ctx context.Context, room *repository.Room) erro
 	return nil
 }
 
+const roomStatusEventSelectAll = "roomStatusEventSelectAll"
+const roomStatusEventSelectAllQuery = `SELECT
+	e.id, e.room_id, e.actor_id, e.from_status, e.to_status, e.note, e.created_at,
+	u.name, u.email
+	FROM room_status_events e
+	LEFT JOIN "users" u ON e.actor_id = u.id
+	WHERE e.room_id = $1
+	ORDER BY e.created_at ASC
+`
+
+func (r *roomRepository) SelectStatusEvents(ctx context.Context, roomId string) ([]*repository.RoomStatusEvent, error) {
+	rows, err := r.ps[roomStatusEventSelectAll].QueryContext(ctx, roomId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*repository.RoomStatusEvent{}
+	for rows.Next() {
+		event := &repository.RoomStatusEvent{}
+		var actorName, actorEmail sql.NullString
+		err := rows.Scan(&event.ID, &event.RoomID, &event.ActorID, &event.FromStatus, &event.ToStatus, &event.Note, &event.CreatedAt,
+			&actorName, &actorEmail,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if event.ActorID.Valid {
+			event.Actor = &repository.User{
+				ID:    event.ActorID.String,
+				Name:  actorName.String,
+				Email: actorEmail.String,
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+const roomSelectMilestonesByGroupID = "roomSelectMilestonesByGroupID"
+const roomSelectMilestonesByGroupIDQuery = `SELECT
+	r.id,
+	MIN(e.created_at) FILTER (WHERE e.to_status = 'IN PROGRESS'),
+	MIN(e.created_at) FILTER (WHERE e.to_status = 'WAITING REVIEW'),
+	MIN(e.created_at) FILTER (WHERE e.to_status IN ('ACCEPTED', 'REJECTED', 'COMPLETED'))
+	FROM rooms r
+	LEFT JOIN room_status_events e ON e.room_id = r.id
+	WHERE r.room_group_id = $1 AND r.deleted = false
+	GROUP BY r.id
+`
+
+func (r *roomRepository) SelectMilestonesByGroupID(ctx context.Context, roomGroupId string) ([]*repository.RoomMilestones, error) {
+	rows, err := r.ps[roomSelectMilestonesByGroupID].QueryContext(ctx, roomGroupId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []*repository.RoomMilestones{}
+	for rows.Next() {
+		milestone := &repository.RoomMilestones{}
+		err := rows.Scan(&milestone.RoomID, &milestone.StartedAt, &milestone.SubmittedAt, &milestone.ReviewedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		milestones = append(milestones, milestone)
+	}
+
+	return milestones, nil
+}
+
 const roomQuestionsDelete = "roomQuestionsDelete"
 const roomQuestionsDeleteQuery = `DELETE FROM ONLY rooms_has_questions
 	WHERE room_id = $1
 `
 
+// the attempts point at the questions of the room, they go first
+const roomAttemptsDelete = "roomAttemptsDelete"
+const roomAttemptsDeleteQuery = `DELETE FROM ONLY answer_attempts
+	WHERE room_id = $1
+`
+
 const roomCompetenciesDelete = "roomComptenciesDelete"
 const roomCompetenciesDeleteQuery = `DELETE FROM ONLY rooms_has_competencies
 	WHERE room_id = $1
@@ -736,11 +1472,16 @@ const roomResultCompetenciesDeleteQuery = `DELETE FROM ONLY results_competencies
 	WHERE room_id = $1
 `
 
+const roomResultSetsDelete = "roomResultSetsDelete"
+const roomResultSetsDeleteQuery = `DELETE FROM ONLY result_sets
+	WHERE room_id = $1
+`
+
 const roomDeleteByID = "roomDeleteByID"
 const roomDeleteByIDQuery = `UPDATE rooms SET
 	deleted = true,
 	deleted_at = $2
-	WHERE id = $1
+	WHERE id = $1 AND org_id = $3
 `
 
 const roomGroupDeleteByID = "roomGroupDeleteByID"
@@ -750,13 +1491,24 @@ const roomGroupDeleteByIDQuery = `UPDATE room_groups SET
 	WHERE id = $1
 `
 
-func (r *roomRepository) DeleteByID(ctx context.Context, roomId string, roomGroupId string) error {
+func (r *roomRepository) DeleteByID(ctx context.Context, orgId, roomId string, roomGroupId string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
-	_, err = tx.StmtContext(ctx, r.ps[roomDeleteByID]).ExecContext(ctx, roomId, time.Now().UTC())
+	res, err := tx.StmtContext(ctx, r.ps[roomDeleteByID]).ExecContext(ctx, roomId, time.Now().UTC(), orgId)
+	if err != nil {
+		return err
+	}
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+	_, err = tx.StmtContext(ctx, r.ps[roomAttemptsDelete]).ExecContext(ctx, roomId)
 	if err != nil {
 		return err
 	}
@@ -772,8 +1524,12 @@ func (r *roomRepository) DeleteByID(ctx context.Context, roomId string, roomGrou
 	if err != nil {
 		return err
 	}
+	_, err = tx.StmtContext(ctx, r.ps[roomResultSetsDelete]).ExecContext(ctx, roomId)
+	if err != nil {
+		return err
+	}
 	//check if room group has no more rooms
-	rows, err := tx.StmtContext(ctx, r.ps[roomSelectAllByRoomGroupID]).QueryContext(ctx, roomGroupId)
+	rows, err := tx.StmtContext(ctx, r.ps[roomSelectAllByRoomGroupID]).QueryContext(ctx, orgId, roomGroupId)
 	if err != nil {
 		return err
 	}
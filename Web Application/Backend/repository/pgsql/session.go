@@ -0,0 +1,242 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+)
+
+type sessionRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewSessionRepository(db *sql.DB) (repository.SessionRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(sessionQueries))
+	for key, query := range sessionQueries {
+		stmt, err := prepareStmt(db, "sessionRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Session Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &sessionRepository{db, ps}, nil
+}
+
+var sessionQueries = map[string]string{
+	sessionInsert:               sessionInsertQuery,
+	sessionRefreshTokenInsert:   refreshTokenInsertQuery,
+	sessionSelectOneByID:        sessionSelectOneByIDQuery,
+	sessionSelectActiveByUserID: sessionSelectActiveByUserIDQuery,
+	sessionSelectRevokedIDs:     sessionSelectRevokedIDsQuery,
+	sessionRevoke:               sessionRevokeQuery,
+	sessionRevokeRefreshTokens:  sessionRevokeRefreshTokensQuery,
+	sessionRevokeAllByUserID:    sessionRevokeAllByUserIDQuery,
+}
+
+const sessionInsert = "sessionInsert"
+const sessionInsertQuery = `INSERT INTO
+	sessions(
+		id, user_id, user_agent, ip_address, expires_at, last_seen_at
+	) values(
+		$1, $2, $3, $4, $5, $6
+	)
+`
+
+const sessionRefreshTokenInsert = "sessionRefreshTokenInsert"
+
+func (r *sessionRepository) Insert(ctx context.Context, session *repository.Session, token *repository.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[sessionInsert]).ExecContext(ctx,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[sessionRefreshTokenInsert]).ExecContext(ctx,
+		token.ID, token.UserID, session.ID, token.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const sessionSelectOneByID = "sessionSelectOneByID"
+const sessionSelectOneByIDQuery = `SELECT
+	id, user_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at
+	FROM sessions
+	WHERE id = $1
+`
+
+func scanSession(row interface{ Scan(...interface{}) error }) (*repository.Session, error) {
+	session := &repository.Session{}
+	err := row.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.ExpiresAt, &session.LastSeenAt, &session.RevokedAt, &session.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) SelectOneByID(ctx context.Context, id string) (*repository.Session, error) {
+	return scanSession(r.ps[sessionSelectOneByID].QueryRowContext(ctx, id))
+}
+
+const sessionSelectActiveByUserID = "sessionSelectActiveByUserID"
+const sessionSelectActiveByUserIDQuery = `SELECT
+	id, user_id, user_agent, ip_address, expires_at, last_seen_at, revoked_at, created_at
+	FROM sessions
+	WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	ORDER BY last_seen_at DESC
+`
+
+func (r *sessionRepository) SelectActiveByUserID(ctx context.Context, userId string) ([]*repository.Session, error) {
+	rows, err := r.ps[sessionSelectActiveByUserID].QueryContext(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*repository.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+const sessionSelectRevokedIDs = "sessionSelectRevokedIDs"
+const sessionSelectRevokedIDsQuery = `SELECT
+	id
+	FROM sessions
+	WHERE revoked_at IS NOT NULL AND expires_at > NOW()
+`
+
+func (r *sessionRepository) SelectRevokedIDs(ctx context.Context) ([]string, error) {
+	rows, err := r.ps[sessionSelectRevokedIDs].QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+const sessionRevoke = "sessionRevoke"
+const sessionRevokeQuery = `UPDATE sessions SET
+	revoked_at = $2
+	WHERE id = $1 AND revoked_at IS NULL
+`
+
+const sessionRevokeRefreshTokens = "sessionRevokeRefreshTokens"
+const sessionRevokeRefreshTokensQuery = `UPDATE refresh_tokens SET
+	revoked_at = $2
+	WHERE session_id = ANY($1::UUID[]) AND revoked_at IS NULL
+`
+
+func (r *sessionRepository) Revoke(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	_, err = tx.StmtContext(ctx, r.ps[sessionRevoke]).ExecContext(ctx, id, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[sessionRevokeRefreshTokens]).ExecContext(ctx, []string{id}, now)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const sessionRevokeAllByUserID = "sessionRevokeAllByUserID"
+const sessionRevokeAllByUserIDQuery = `UPDATE sessions SET
+	revoked_at = $2
+	WHERE user_id = $1 AND revoked_at IS NULL AND ($3::UUID IS NULL OR id <> $3::UUID)
+	RETURNING id
+`
+
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userId, exceptId string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	rows, err := tx.StmtContext(ctx, r.ps[sessionRevokeAllByUserID]).QueryContext(ctx,
+		userId, now, sql.NullString{String: exceptId, Valid: exceptId != ""},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[sessionRevokeRefreshTokens]).ExecContext(ctx, ids, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,377 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"interview/summarization/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type twoFactorRepository struct {
+	db *sql.DB
+	ps map[string]*sql.Stmt
+}
+
+func NewTwoFactorRepository(db *sql.DB) (repository.TwoFactorRepository, error) {
+	ps := make(map[string]*sql.Stmt, len(twoFactorQueries))
+	for key, query := range twoFactorQueries {
+		stmt, err := prepareStmt(db, "twoFactorRepository", key, query)
+		if err != nil {
+			return nil, fmt.Errorf("Two Factor Repository: %w", err)
+		}
+
+		ps[key] = stmt
+	}
+
+	return &twoFactorRepository{db, ps}, nil
+}
+
+var twoFactorQueries = map[string]string{
+	twoFactorSelectByUserID:      twoFactorSelectByUserIDQuery,
+	twoFactorUpsertPending:       twoFactorUpsertPendingQuery,
+	twoFactorEnable:              twoFactorEnableQuery,
+	twoFactorUseStep:             twoFactorUseStepQuery,
+	twoFactorUseRecoveryCode:     twoFactorUseRecoveryCodeQuery,
+	twoFactorDeleteRecoveryCodes: twoFactorDeleteRecoveryCodesQuery,
+	twoFactorInsertRecoveryCodes: twoFactorInsertRecoveryCodesQuery,
+	twoFactorCountRecoveryCodes:  twoFactorCountRecoveryCodesQuery,
+	twoFactorDelete:              twoFactorDeleteQuery,
+	twoFactorSelectRequiredRoles: twoFactorSelectRequiredRolesQuery,
+	twoFactorIsRequired:          twoFactorIsRequiredQuery,
+	twoFactorDeleteRequiredRoles: twoFactorDeleteRequiredRolesQuery,
+	twoFactorInsertRequiredRoles: twoFactorInsertRequiredRolesQuery,
+}
+
+const twoFactorSelectByUserID = "twoFactorSelectByUserID"
+const twoFactorSelectByUserIDQuery = `SELECT user_id, secret, enabled_at, last_used_step, created_at
+	FROM two_factors WHERE user_id = $1
+`
+
+func (r *twoFactorRepository) SelectByUserID(ctx context.Context, userId string) (*repository.TwoFactor, error) {
+	twoFactor := &repository.TwoFactor{}
+
+	row := r.ps[twoFactorSelectByUserID].QueryRowContext(ctx, userId)
+	err := row.Scan(
+		&twoFactor.UserID, &twoFactor.Secret, &twoFactor.EnabledAt, &twoFactor.LastUsedStep, &twoFactor.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return twoFactor, nil
+}
+
+const twoFactorUpsertPending = "twoFactorUpsertPending"
+const twoFactorUpsertPendingQuery = `INSERT INTO
+	two_factors(
+		user_id, secret, created_at
+	) values(
+		$1, $2, $3
+	)
+	ON CONFLICT (user_id) DO UPDATE SET
+		secret = $2,
+		last_used_step = 0,
+		created_at = $3
+	WHERE two_factors.enabled_at IS NULL
+`
+
+func (r *twoFactorRepository) UpsertPending(ctx context.Context, userId, secret string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[twoFactorUpsertPending]).ExecContext(ctx, userId, secret, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return repository.ErrTwoFactorEnabled
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorEnable = "twoFactorEnable"
+const twoFactorEnableQuery = `UPDATE two_factors SET
+	enabled_at = $3,
+	last_used_step = $2
+	WHERE user_id = $1 AND enabled_at IS NULL
+`
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userId string, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[twoFactorEnable]).ExecContext(ctx, userId, step, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err := r.replaceRecoveryCodes(ctx, tx, userId, codeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorUseStep = "twoFactorUseStep"
+const twoFactorUseStepQuery = `UPDATE two_factors SET
+	last_used_step = $2
+	WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+`
+
+func (r *twoFactorRepository) UseStep(ctx context.Context, userId string, step int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[twoFactorUseStep]).ExecContext(ctx, userId, step)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorUseRecoveryCode = "twoFactorUseRecoveryCode"
+const twoFactorUseRecoveryCodeQuery = `UPDATE recovery_codes SET
+	used_at = $3
+	WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userId, codeHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[twoFactorUseRecoveryCode]).ExecContext(ctx, userId, codeHash, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorDeleteRecoveryCodes = "twoFactorDeleteRecoveryCodes"
+const twoFactorDeleteRecoveryCodesQuery = `DELETE FROM recovery_codes WHERE user_id = $1`
+
+const twoFactorInsertRecoveryCodes = "twoFactorInsertRecoveryCodes"
+const twoFactorInsertRecoveryCodesQuery = `INSERT INTO
+	recovery_codes(
+		id, user_id, code_hash
+	)
+	SELECT unnest($1::UUID[]), $2, unnest($3::TEXT[])
+`
+
+func (r *twoFactorRepository) replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId string, codeHashes []string) error {
+	_, err := tx.StmtContext(ctx, r.ps[twoFactorDeleteRecoveryCodes]).ExecContext(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(codeHashes))
+	for i := range codeHashes {
+		ids[i] = uuid.NewString()
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[twoFactorInsertRecoveryCodes]).ExecContext(ctx, ids, userId, codeHashes)
+	return err
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.replaceRecoveryCodes(ctx, tx, userId, codeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorCountRecoveryCodes = "twoFactorCountRecoveryCodes"
+const twoFactorCountRecoveryCodesQuery = `SELECT COUNT(*)
+	FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (r *twoFactorRepository) CountRecoveryCodes(ctx context.Context, userId string) (int, error) {
+	var count int
+
+	row := r.ps[twoFactorCountRecoveryCodes].QueryRowContext(ctx, userId)
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+const twoFactorDelete = "twoFactorDelete"
+const twoFactorDeleteQuery = `DELETE FROM two_factors WHERE user_id = $1`
+
+func (r *twoFactorRepository) Delete(ctx context.Context, userId string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[twoFactorDeleteRecoveryCodes]).ExecContext(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[twoFactorDelete]).ExecContext(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const twoFactorSelectRequiredRoles = "twoFactorSelectRequiredRoles"
+const twoFactorSelectRequiredRolesQuery = `SELECT role
+	FROM two_factor_roles WHERE org_id = $1 ORDER BY role
+`
+
+func (r *twoFactorRepository) SelectRequiredRoles(ctx context.Context, orgId string) ([]repository.UserRole, error) {
+	rows, err := r.ps[twoFactorSelectRequiredRoles].QueryContext(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []repository.UserRole{}
+	for rows.Next() {
+		var role repository.UserRole
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+const twoFactorIsRequired = "twoFactorIsRequired"
+const twoFactorIsRequiredQuery = `SELECT EXISTS(
+	SELECT 1 FROM two_factor_roles WHERE org_id = $1 AND role = $2
+)`
+
+func (r *twoFactorRepository) IsRequired(ctx context.Context, orgId string, role repository.UserRole) (bool, error) {
+	var isRequired bool
+
+	row := r.ps[twoFactorIsRequired].QueryRowContext(ctx, orgId, role)
+	if err := row.Scan(&isRequired); err != nil {
+		return false, err
+	}
+
+	return isRequired, nil
+}
+
+const twoFactorDeleteRequiredRoles = "twoFactorDeleteRequiredRoles"
+const twoFactorDeleteRequiredRolesQuery = `DELETE FROM two_factor_roles WHERE org_id = $1`
+
+const twoFactorInsertRequiredRoles = "twoFactorInsertRequiredRoles"
+const twoFactorInsertRequiredRolesQuery = `INSERT INTO
+	two_factor_roles(
+		org_id, role
+	)
+	SELECT $1, unnest($2::TEXT[])
+	ON CONFLICT DO NOTHING
+`
+
+func (r *twoFactorRepository) UpdateRequiredRoles(ctx context.Context, orgId string, roles []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, r.ps[twoFactorDeleteRequiredRoles]).ExecContext(ctx, orgId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, r.ps[twoFactorInsertRequiredRoles]).ExecContext(ctx, orgId, roles)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -32,19 +32,26 @@ var userQueries = map[string]string{
 	userSelectAll:                   userSelectAllQuery,
 	userSelectIDPasswordRoleByEmail: userSelectIDPasswordRoleByEmailQuery,
 	userSelectIDByEmail:             userSelectIDByEmailQuery,
+	userSelectIDByEmailInOrg:        userSelectIDByEmailInOrgQuery,
 	userSelectNamePhoneEmailByID:    userSelectNamePhoneEmailByIDQuery,
 	userSelectPasswordByID:          userSelectPasswordByIDQuery,
 	userUpdate:                      userUpdateQuery,
 	userUpdatePassword:              userUpdatePasswordQuery,
 	userUpdateStatus:                userUpdateStatusQuery,
+	userVerify:                      userVerifyQuery,
+	userMarkVerificationSent:        userMarkVerificationSentQuery,
+	userSelectAllByFilter:           userSelectAllByFilterQuery,
+	userSelectOneByID:               userSelectOneByIDQuery,
+	userSelectOrgRoleStatusByID:     userSelectOrgRoleStatusByIDQuery,
+	userUpdateRole:                  userUpdateRoleQuery,
 }
 
 const userInsert = "userInsert"
 const userInsertQuery = `INSERT INTO
 	"users"(
-		id, name, phone, email, password, role, status
+		id, org_id, name, phone, email, password, role, status, verification_sent_at
 	) values(
-		$1, $2, $3, $4, $5, $6, $7
+		$1, $2, $3, $4, $5, $6, $7, $8, $9
 	)
 `
 
@@ -56,7 +63,7 @@ func (r *userRepository) Insert(ctx context.Context, user *repository.User) erro
 	defer tx.Rollback()
 
 	_, err = tx.StmtContext(ctx, r.ps[userInsert]).ExecContext(ctx,
-		user.ID, user.Name, user.Phone, user.Email, user.Password, user.Role, user.Status,
+		user.ID, user.OrgID, user.Name, user.Phone, user.Email, user.Password, user.Role, user.Status, user.VerificationSentAt,
 	)
 	if err != nil {
 		return err
@@ -72,11 +79,11 @@ func (r *userRepository) Insert(ctx context.Context, user *repository.User) erro
 const userSelectAll = "userSelectAll"
 const userSelectAllQuery = `SELECT id, name, phone, email, role
 	FROM "users"
-	WHERE status = 'VERIFIED'
+	WHERE org_id = $1 AND status = 'VERIFIED'
 `
 
-func (r *userRepository) SelectAll(ctx context.Context) ([]*repository.User, error) {
-	rows, err := r.ps[userSelectAll].QueryContext(ctx)
+func (r *userRepository) SelectAll(ctx context.Context, orgId string) ([]*repository.User, error) {
+	rows, err := r.ps[userSelectAll].QueryContext(ctx, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +105,79 @@ func (r *userRepository) SelectAll(ctx context.Context) ([]*repository.User, err
 	return users, nil
 }
 
+const userSelectAllByFilter = "userSelectAllByFilter"
+const userSelectAllByFilterQuery = `SELECT id, name, phone, email, role, status, created_at
+	FROM "users"
+	WHERE org_id = $1
+	AND ($2 = '' OR role = $2)
+	AND ($3 = '' OR status = $3)
+	AND ($4 = '' OR name ILIKE '%' || $4 || '%' OR email ILIKE '%' || $4 || '%')
+	ORDER BY created_at DESC
+`
+
+func (r *userRepository) SelectAllByFilter(ctx context.Context, orgId string, filter repository.UserFilter) ([]*repository.User, error) {
+	rows, err := r.ps[userSelectAllByFilter].QueryContext(ctx, orgId, filter.Role, filter.Status, filter.Search)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*repository.User, 0)
+	for rows.Next() {
+		user := &repository.User{}
+		err := rows.Scan(
+			&user.ID, &user.Name, &user.Phone, &user.Email, &user.Role, &user.Status, &user.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+const userSelectOneByID = "userSelectOneByID"
+const userSelectOneByIDQuery = `SELECT id, name, phone, email, role, status, created_at
+	FROM "users" WHERE org_id = $1 AND id = $2
+`
+
+func (r *userRepository) SelectOneByID(ctx context.Context, orgId, id string) (*repository.User, error) {
+	user := &repository.User{}
+
+	row := r.ps[userSelectOneByID].QueryRowContext(ctx, orgId, id)
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Phone, &user.Email, &user.Role, &user.Status, &user.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+const userSelectOrgRoleStatusByID = "userSelectOrgRoleStatusByID"
+const userSelectOrgRoleStatusByIDQuery = `SELECT id, org_id, role, status
+	FROM "users" WHERE id = $1
+`
+
+func (r *userRepository) SelectOrgRoleStatusByID(ctx context.Context, id string) (*repository.User, error) {
+	user := &repository.User{}
+
+	row := r.ps[userSelectOrgRoleStatusByID].QueryRowContext(ctx, id)
+	err := row.Scan(
+		&user.ID, &user.OrgID, &user.Role, &user.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 const userSelectIDPasswordRoleByEmail = "userSelectIDPasswordRoleByEmail"
-const userSelectIDPasswordRoleByEmailQuery = `SELECT id, password, role, status
+const userSelectIDPasswordRoleByEmailQuery = `SELECT id, org_id, password, role, status
 	FROM "users" WHERE email = $1
 `
 
@@ -108,7 +186,7 @@ func (r *userRepository) SelectIDPasswordRoleByEmail(ctx context.Context, email
 
 	row := r.ps[userSelectIDPasswordRoleByEmail].QueryRowContext(ctx, email)
 	err := row.Scan(
-		&user.ID, &user.Password, &user.Role, &user.Status,
+		&user.ID, &user.OrgID, &user.Password, &user.Role, &user.Status,
 	)
 	if err != nil {
 		return nil, err
@@ -136,6 +214,25 @@ func (r *userRepository) SelectIDByEmail(ctx context.Context, email string) (*re
 	return user, nil
 }
 
+const userSelectIDByEmailInOrg = "userSelectIDByEmailInOrg"
+const userSelectIDByEmailInOrgQuery = `SELECT id, name, email
+	FROM "users" WHERE org_id = $1 AND email = $2
+`
+
+func (r *userRepository) SelectIDByEmailInOrg(ctx context.Context, orgId, email string) (*repository.User, error) {
+	user := &repository.User{}
+
+	row := r.ps[userSelectIDByEmailInOrg].QueryRowContext(ctx, orgId, email)
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Email,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 const userSelectNamePhoneEmailByID = "userSelectNamePhoneEmailByID"
 const userSelectNamePhoneEmailByIDQuery = `SELECT name, phone, email
 	FROM "users" WHERE id = $1
@@ -285,3 +382,107 @@ func (r *userRepository) UpdateStatus(ctx context.Context, user *repository.User
 
 	return nil
 }
+
+const userVerify = "userVerify"
+const userVerifyQuery = `UPDATE "users" SET
+	status = 'VERIFIED',
+	updated_at = $2
+	WHERE id = $1 AND status = 'UNVERIFIED'
+`
+
+func (r *userRepository) Verify(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[userVerify]).ExecContext(ctx, id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const userUpdateRole = "userUpdateRole"
+const userUpdateRoleQuery = `UPDATE "users" SET
+	role = $2,
+	updated_at = $3
+	WHERE id = $1 AND org_id = $4
+`
+
+func (r *userRepository) UpdateRole(ctx context.Context, user *repository.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	updatedAt := time.Now().UTC()
+	res, err := tx.StmtContext(ctx, r.ps[userUpdateRole]).ExecContext(ctx,
+		user.ID, user.Role, updatedAt, user.OrgID,
+	)
+	if err != nil {
+		return err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updatedRows != 1 {
+		return sql.ErrNoRows
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const userMarkVerificationSent = "userMarkVerificationSent"
+const userMarkVerificationSentQuery = `UPDATE "users" SET
+	verification_sent_at = $2
+	WHERE id = $1 AND status = 'UNVERIFIED'
+	AND (verification_sent_at IS NULL OR verification_sent_at <= $3)
+`
+
+func (r *userRepository) MarkVerificationSent(ctx context.Context, id string, sentAt time.Time, cooldown time.Duration) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.StmtContext(ctx, r.ps[userMarkVerificationSent]).ExecContext(ctx,
+		id, sentAt, sentAt.Add(-cooldown),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	updatedRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return updatedRows == 1, nil
+}
@@ -8,6 +8,7 @@ import (
 
 type Question struct {
 	ID            string
+	OrgID         string
 	Question      string
 	DurationLimit int
 	OrgPosition   string
@@ -35,9 +36,9 @@ type Labels struct {
 
 type QuestionRepository interface {
 	Insert(context.Context, *Question, *Labels) error
-	SelectAll(context.Context) ([]*Question, error)
+	SelectAll(context.Context, string) ([]*Question, error)
 	SelectAllByRoomID(context.Context, string) ([]*Question, error)
-	SelectOneByID(context.Context, string) (*Question, error)
+	SelectOneByID(context.Context, string, string) (*Question, error)
 	Upsert(context.Context, *Question, *Labels) error
-	DeleteByID(context.Context, string) error
+	DeleteByID(context.Context, string, string) error
 }
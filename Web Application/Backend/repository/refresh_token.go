@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrTokenReused = errors.New("refresh token reused")
+
+// RefreshToken is the server side record of an issued refresh token. Every
+// token rotated out of the same login belongs to the same session, so a
+// stolen token can take the whole session down with it.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	SessionID  string
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	ReplacedBy sql.NullString
+	CreatedAt  time.Time
+}
+
+type RefreshTokenRepository interface {
+	SelectOneByID(context.Context, string) (*RefreshToken, error)
+	// Rotate revokes the token and stores its replacement. It returns
+	// ErrTokenReused when the token or its session was already revoked.
+	Rotate(context.Context, string, *RefreshToken) error
+}
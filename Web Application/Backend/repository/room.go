@@ -3,34 +3,140 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 )
 
+var ErrInvalidTransition = errors.New("invalid status transition")
+
 type RoomStatus string
 
 const (
 	WaitingAnswer = RoomStatus("WAITING ANSWER")
+	InProgress = RoomStatus("IN PROGRESS")
 	WaitingReview = RoomStatus("WAITING REVIEW")
 	Accepted = RoomStatus("ACCEPTED")
 	Rejected = RoomStatus("REJECTED")
 	Completed = RoomStatus("COMPLETED")
+	Expired = RoomStatus("EXPIRED")
+	Cancelled = RoomStatus("CANCELLED")
 )
 
 func RoomStatusMapper(status string) (RoomStatus, bool) {
 	mapper := map[string]RoomStatus{
 		"WAITING ANSWER":   WaitingAnswer,
+		"IN PROGRESS":      InProgress,
 		"WAITING REVIEW":   WaitingReview,
 		"COMPLETED": 				Completed,
 		"ACCEPTED":					Accepted,
 		"REJECTED":					Rejected,
+		"EXPIRED":					Expired,
+		"CANCELLED":				Cancelled,
 	}
 
 	roomStatus, ok := mapper[status]
 	return roomStatus, ok
 }
 
+// roomTransitions lists the statuses a room may move to from each status.
+// A room waiting for its answer may be saved again while its questions are
+// edited, a candidate coming back to a room in progress keeps it there, and
+// an expired room is still reviewed with the answers given before its end.
+var roomTransitions = map[RoomStatus][]RoomStatus{
+	WaitingAnswer: {WaitingAnswer, InProgress, Expired, Cancelled},
+	InProgress:    {InProgress, WaitingReview, Expired, Cancelled},
+	WaitingReview: {Accepted, Rejected, Completed, Cancelled},
+	Expired:       {Accepted, Rejected, Completed, Cancelled},
+	Accepted:      {},
+	Rejected:      {},
+	Completed:     {},
+	Cancelled:     {},
+}
+
+func (s RoomStatus) CanTransitionTo(to RoomStatus) bool {
+	for _, next := range roomTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoomStatusSources returns every status from which a room may move to the given status.
+func RoomStatusSources(to RoomStatus) []string {
+	sources := []string{}
+	for from := range roomTransitions {
+		if from.CanTransitionTo(to) {
+			sources = append(sources, string(from))
+		}
+	}
+
+	return sources
+}
+
+// IsOpen tells whether the candidate may still work on the room.
+func (s RoomStatus) IsOpen() bool {
+	return s == WaitingAnswer || s == InProgress
+}
+
+type AnswerStatus string
+
+const (
+	AnswerNotSubmitted = AnswerStatus("NOT_SUBMITTED")
+	AnswerQueued       = AnswerStatus("QUEUED")
+	AnswerTranscribing = AnswerStatus("TRANSCRIBING")
+	AnswerTranscribed  = AnswerStatus("TRANSCRIBED")
+	AnswerScoring      = AnswerStatus("SCORING")
+	AnswerScored       = AnswerStatus("SCORED")
+	AnswerFailed       = AnswerStatus("FAILED")
+)
+
+// answerTransitions lists the states an answer may move to from each state.
+// Staying in the same state is allowed so a job re-run after a lost lease
+// can record its progress again, and a transcription waiting for its retry
+// goes back to QUEUED.
+var answerTransitions = map[AnswerStatus][]AnswerStatus{
+	AnswerNotSubmitted: {AnswerQueued},
+	AnswerQueued:       {AnswerQueued, AnswerTranscribing, AnswerFailed},
+	AnswerTranscribing: {AnswerQueued, AnswerTranscribing, AnswerTranscribed, AnswerFailed},
+	AnswerTranscribed:  {AnswerQueued, AnswerTranscribed, AnswerScoring, AnswerFailed},
+	AnswerScoring:      {AnswerTranscribed, AnswerScoring, AnswerScored, AnswerFailed},
+	AnswerScored:       {AnswerQueued, AnswerScoring},
+	AnswerFailed:       {AnswerQueued, AnswerScoring},
+}
+
+// IsProcessing reports whether a job is still working on the answer, it
+// can't be replaced by another submission until the job is done.
+func (s AnswerStatus) IsProcessing() bool {
+	return s == AnswerQueued || s == AnswerTranscribing || s == AnswerScoring
+}
+
+func (s AnswerStatus) CanTransitionTo(to AnswerStatus) bool {
+	for _, next := range answerTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AnswerStatusSources returns every state from which an answer may move to the given state.
+func AnswerStatusSources(to AnswerStatus) []string {
+	sources := []string{}
+	for from := range answerTransitions {
+		if from.CanTransitionTo(to) {
+			sources = append(sources, string(from))
+		}
+	}
+
+	return sources
+}
+
 type RoomGroup struct {
 	ID        		string
+	OrgID 				string
 	Title 	 			string
 	OrgPosition 	string
 	IntervieweeID string
@@ -40,6 +146,7 @@ type RoomGroup struct {
 
 type Room struct {
 	ID            string
+	OrgID         string
 	InterviewerID string
 	RoomGroupID   string
 	Title         string
@@ -53,6 +160,9 @@ type Room struct {
 	Note          sql.NullString
 	Language			string
 	PrepationTime int
+	ShuffleQuestions bool
+	MaxAttempts   int
+	AttemptRule   AttemptRule
 	Deleted       bool
 	CreatedAt     time.Time
 	UpdatedAt     sql.NullTime
@@ -60,6 +170,23 @@ type Room struct {
 	Interviewer   *User
 }
 
+// ResultSet is one scoring run of a room. Every run is kept so a room can
+// be scored again without losing the earlier results, along with the model
+// and the input that produced it.
+type ResultSet struct {
+	ID             string
+	RoomID         string
+	JobID          sql.NullString
+	ModelVersion   sql.NullString
+	Language       string
+	TranscriptHash string
+	CreatedAt      time.Time
+	Results        ResultCompetency
+	// Feedback is stored along with the results it was taken from, a
+	// rescore leaves it empty.
+	Feedback       []*Feedback
+}
+
 type ResultCompetency map[string]map[string]float64
 
 type ResultQuestion map[string]string
@@ -68,29 +195,102 @@ type QuestionInRoom struct {
 	ID							string
 	Question				string
 	DurationLimit		int
-	StartAnswer			sql.NullString
+	StartAnswer			sql.NullTime
+	AnswerStatus		AnswerStatus
+	SubmittedAt			sql.NullTime
+}
+
+// RoomStatusEvent records one status change of a room. Changes made by the
+// scheduler have no actor.
+type RoomStatusEvent struct {
+	ID         string
+	RoomID     string
+	ActorID    sql.NullString
+	FromStatus sql.NullString
+	ToStatus   RoomStatus
+	Note       sql.NullString
+	CreatedAt  time.Time
+	Actor      *User
+}
+
+// RoomOwners are the users a room belongs to, a room created outside of a
+// room group has no interviewee.
+type RoomOwners struct {
+	RoomID        string
+	InterviewerID string
+	IntervieweeID sql.NullString
+}
+
+// RoomGroupOwners are the interviewee of a room group and the interviewers
+// of its rooms.
+type RoomGroupOwners struct {
+	RoomGroupID    string
+	IntervieweeID  sql.NullString
+	InterviewerIDs []string
+}
+
+// RoomMilestones holds when a room first reached the statuses that mark its
+// start, its submission and its review.
+type RoomMilestones struct {
+	RoomID      string
+	StartedAt   sql.NullTime
+	SubmittedAt sql.NullTime
+	ReviewedAt  sql.NullTime
+}
+
+type AnswerProcessing struct {
+	QuestionID      string
+	Question        string
+	Status          AnswerStatus
+	LastError       sql.NullString
+	QueuedAt        sql.NullTime
+	TranscribedAt   sql.NullTime
+	ScoredAt        sql.NullTime
+	FailedAt        sql.NullTime
+	StatusUpdatedAt sql.NullTime
+	SubmittedAt     sql.NullTime
+	IsLate          bool
 }
 
+// RoomRepository finds rooms and room groups within an organization. The
+// methods taking only a room id work on a room already found that way, or
+// run for the job queue and the scheduler across every organization.
 type RoomRepository interface {
 	InsertRoomGroup(context.Context, *RoomGroup) error
-	Insert(context.Context, *Room, []string, []string) error
-	UpdateQuestionsAndCompetenciesRoom(context.Context, string, []string, []string, RoomStatus) error
-	SelectAllRoomGroup(context.Context) ([]*RoomGroup, error)
-	SelectAllRoomGroupByInterviewerID(context.Context, string) ([]*RoomGroup, error)
-	SelectAllRoomGroupByIntervieweeID(context.Context, string) ([]*RoomGroup, error)
-	SelectAllRoomByGroupID(context.Context, string) ([]*Room, error)
-	SelectRoomGroupByID(context.Context, string) (*RoomGroup, error)
-	SelectOneRoomByID(context.Context, string) (*Room, error)
+	Insert(context.Context, *Room, []string, []string, string) error
+	UpdateQuestionsAndCompetenciesRoom(context.Context, string, string, []string, []string, RoomStatus, string) error
+	SelectAllRoomGroup(context.Context, string) ([]*RoomGroup, error)
+	SelectAllRoomGroupByInterviewerID(context.Context, string, string) ([]*RoomGroup, error)
+	SelectAllRoomGroupByIntervieweeID(context.Context, string, string) ([]*RoomGroup, error)
+	SelectAllRoomByGroupID(context.Context, string, string) ([]*Room, error)
+	SelectRoomGroupByID(context.Context, string, string) (*RoomGroup, error)
+	SelectOneRoomByID(context.Context, string, string) (*Room, error)
+	SelectOwnersByID(context.Context, string, string) (*RoomOwners, error)
+	SelectGroupOwnersByID(context.Context, string, string) (*RoomGroupOwners, error)
 	InsertTranscript(context.Context, string, string, string, string) error
 	IsAnswered(context.Context, string) (bool, error)
 	GetAnswers(context.Context, string) (string, error)
-	InsertResult(context.Context, string, []string, []string, []float64) error
+	// InsertResult stores the result set with its scores and feedback at
+	// once, so a job that finds its result set has nothing left to store.
+	InsertResult(context.Context, *ResultSet, []string, []string, []float64) error
+	IsScoredByJob(context.Context, string) (bool, error)
+	SelectLatestResultSet(context.Context, string) (*ResultSet, error)
+	SelectResultSets(context.Context, string) ([]*ResultSet, error)
 	GetResultCompetencies(context.Context, string) (ResultCompetency, error)
 	GetResultQuestions(context.Context, string) (ResultQuestion, error)
+	SelectQuestionsInRoom(context.Context, string) ([]*QuestionInRoom, error)
+	ReorderQuestions(context.Context, string, []string) error
 	GetOneQuestionByRoomID(context.Context, string, string) (*QuestionInRoom, error)
-	UpdateStatusAndSubmission(context.Context, *Room) error
-	UpdateQuestionByRoomID(context.Context, string, string, string) error
+	ExpireRooms(context.Context, time.Time) (int64, error)
+	UpdateStatus(context.Context, string, RoomStatus, string) error
+	UpdateStatusAndSubmission(context.Context, *Room, string) error
+	SelectStatusEvents(context.Context, string) ([]*RoomStatusEvent, error)
+	SelectMilestonesByGroupID(context.Context, string) ([]*RoomMilestones, error)
+	UpdateQuestionByRoomID(context.Context, string, string, time.Time) error
 	UpdateRoomQuestionCond(context.Context, string, int, bool) error
-	Review(context.Context, *Room) error
-	DeleteByID(context.Context, string, string) error
+	UpdateAnswerStatus(context.Context, string, string, AnswerStatus, string) error
+	UpdateRoomAnswersStatus(context.Context, string, AnswerStatus, string) error
+	SelectAnswerProcessing(context.Context, string) ([]*AnswerProcessing, error)
+	Review(context.Context, *Room, string) error
+	DeleteByID(context.Context, string, string, string) error
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Session is a login on one device. It lives as long as its latest refresh
+// token and every access token issued for it carries its id.
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IPAddress  string
+	ExpiresAt  time.Time
+	LastSeenAt time.Time
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type SessionRepository interface {
+	// Insert stores the session together with its first refresh token.
+	Insert(context.Context, *Session, *RefreshToken) error
+	SelectOneByID(context.Context, string) (*Session, error)
+	SelectActiveByUserID(context.Context, string) ([]*Session, error)
+	// SelectRevokedIDs lists the revoked sessions that haven't expired yet,
+	// access tokens of older sessions are rejected by their expiry anyway.
+	SelectRevokedIDs(context.Context) ([]string, error)
+	// Revoke ends the session and every refresh token issued for it.
+	Revoke(context.Context, string) error
+	// RevokeAllByUserID ends every session of the user but the given one,
+	// which may be empty, and returns the sessions it ended.
+	RevokeAllByUserID(context.Context, string, string) ([]string, error)
+}
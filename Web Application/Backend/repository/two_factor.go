@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+
+// TwoFactor is the TOTP secret of a user. It only counts once enabled, until
+// then it is waiting for the first code of the authenticator it was shared
+// with. LastUsedStep keeps a code from being used twice.
+type TwoFactor struct {
+	UserID       string
+	Secret       string
+	EnabledAt    sql.NullTime
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+type TwoFactorRepository interface {
+	SelectByUserID(context.Context, string) (*TwoFactor, error)
+	// UpsertPending stores a new secret waiting to be enabled, it returns
+	// ErrTwoFactorEnabled when the user already has one enabled.
+	UpsertPending(context.Context, string, string) error
+	// Enable turns the pending secret on with the step of its first code and
+	// replaces the recovery codes with the hashes given.
+	Enable(context.Context, string, int64, []string) error
+	// UseStep records the step of a code that signed the user in, it returns
+	// sql.ErrNoRows when a code of that step or a later one was used already.
+	UseStep(context.Context, string, int64) error
+	// UseRecoveryCode uses up the unused recovery code with the hash or
+	// returns sql.ErrNoRows.
+	UseRecoveryCode(context.Context, string, string) error
+	ReplaceRecoveryCodes(context.Context, string, []string) error
+	CountRecoveryCodes(context.Context, string) (int, error)
+	// Delete turns two-factor authentication off for the user.
+	Delete(context.Context, string) error
+
+	// SelectRequiredRoles lists the roles of the organization that cannot
+	// sign in without a second factor.
+	SelectRequiredRoles(context.Context, string) ([]UserRole, error)
+	IsRequired(context.Context, string, UserRole) (bool, error)
+	UpdateRequiredRoles(context.Context, string, []string) error
+}
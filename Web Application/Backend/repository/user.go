@@ -18,6 +18,7 @@ func UserRoleMapper(role string) (UserRole, bool) {
 	mapper := map[string]UserRole{
 		"INTERVIEWER": Interviewer,
 		"INTERVIEWEE": Interviewee,
+		"HRD":         Hrd,
 	}
 
 	userRole, ok := mapper[role]
@@ -29,12 +30,16 @@ type UserStatus string
 const (
 	Veryfied = UserStatus("VERIFIED")
 	Unverified = UserStatus("UNVERIFIED")
+	Invited = UserStatus("INVITED")
+	Deactivated = UserStatus("DEACTIVATED")
 )
 
 func UserStatusMapper(status string) (UserStatus, bool) {
 	mapper := map[string]UserStatus{
 		"VERIFIED": Veryfied,
 		"UNVERIFIED": Unverified,
+		"INVITED": Invited,
+		"DEACTIVATED": Deactivated,
 	}
 
 	userStatus, ok := mapper[status]
@@ -43,26 +48,50 @@ func UserStatusMapper(status string) (UserStatus, bool) {
 
 type User struct {
 	ID        string
+	OrgID     string
 	Name      string
 	Phone     string
 	Email     string
 	Password  string
 	Role      UserRole
 	Status    UserStatus
+	VerificationSentAt sql.NullTime
 	Deleted   bool
 	CreatedAt time.Time
 	UpdatedAt sql.NullTime
 	DeletedAt sql.NullTime
 }
 
+// UserFilter narrows down the users listed to HRD, empty fields match
+// every user.
+type UserFilter struct {
+	Role   UserRole
+	Status UserStatus
+	Search string
+}
+
 type UserRepository interface {
 	Insert(context.Context, *User) error
-	SelectAll(context.Context) ([]*User, error)
+	SelectAll(context.Context, string) ([]*User, error)
+	SelectAllByFilter(context.Context, string, UserFilter) ([]*User, error)
+	SelectOneByID(context.Context, string, string) (*User, error)
+	// SelectOrgRoleStatusByID looks up the organization a signed in user
+	// acts for, it is the only lookup by id across organizations.
+	SelectOrgRoleStatusByID(context.Context, string) (*User, error)
 	SelectIDPasswordRoleByEmail(context.Context, string) (*User, error)
 	SelectIDByEmail(context.Context, string) (*User, error)
+	SelectIDByEmailInOrg(context.Context, string, string) (*User, error)
 	SelectNamePhoneEmailByID(context.Context, string) (*User, error)
 	SelectPasswordByID(context.Context, string) (*User, error)
 	Update(context.Context, *User) error
 	UpdatePassword(context.Context, *User) error
 	UpdateStatus(context.Context, *User) error
+	// Verify marks an unverified user as verified, users who are already
+	// verified, invited or deactivated are reported as not found.
+	Verify(context.Context, string) error
+	UpdateRole(context.Context, *User) error
+	// MarkVerificationSent records a new verification email for an
+	// unverified user, unless the last one went out less than the cooldown
+	// ago. It reports whether the email may be sent.
+	MarkVerificationSent(context.Context, string, time.Time, time.Duration) (bool, error)
 }
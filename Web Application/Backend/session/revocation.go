@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"interview/summarization/repository"
+	"log"
+	"sync"
+	"time"
+)
+
+const defaultRefreshInterval = 30 // seconds
+
+// RevocationList keeps the revoked sessions in memory so checking a bearer
+// token doesn't cost a query. Sessions revoked by this server are known at
+// once, the ones revoked elsewhere show up on the next reload. Until the
+// first load succeeds every session counts as revoked.
+type RevocationList struct {
+	sessionRepository repository.SessionRepository
+	interval          time.Duration
+
+	mu        sync.Mutex
+	revoked   map[string]struct{}
+	loadedAt  time.Time
+	reloading bool
+	// added remembers when this server revoked a session, a reload that
+	// started earlier may have missed it.
+	added map[string]time.Time
+}
+
+func NewRevocationList(sessionRepository repository.SessionRepository, refreshInterval int) *RevocationList {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	return &RevocationList{
+		sessionRepository: sessionRepository,
+		interval:          time.Duration(refreshInterval) * time.Second,
+		revoked:           map[string]struct{}{},
+		added:             map[string]time.Time{},
+	}
+}
+
+func (l *RevocationList) IsRevoked(ctx context.Context, sessionId string) bool {
+	l.mu.Lock()
+	loaded := !l.loadedAt.IsZero()
+	// one request reloads a stale list while the others keep using it, as
+	// long as there is nothing to use every request tries
+	reload := time.Since(l.loadedAt) > l.interval && (!loaded || !l.reloading)
+	if reload {
+		l.reloading = true
+	}
+	l.mu.Unlock()
+
+	if reload {
+		if err := l.reload(ctx); err != nil {
+			log.Println("session: failed to reload revoked sessions:", err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if reload {
+		l.reloading = false
+	}
+	if l.loadedAt.IsZero() {
+		return true
+	}
+
+	_, ok := l.revoked[sessionId]
+	return ok
+}
+
+// reload swaps in the revoked sessions of the database, keeping the ones
+// revoked by this server since the query started.
+func (l *RevocationList) reload(ctx context.Context) error {
+	startedAt := time.Now()
+	ids, err := l.sessionRepository.SelectRevokedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	revoked := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		revoked[id] = struct{}{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for id, addedAt := range l.added {
+		if addedAt.Before(startedAt) {
+			delete(l.added, id)
+			continue
+		}
+
+		revoked[id] = struct{}{}
+	}
+	l.revoked = revoked
+	l.loadedAt = startedAt
+
+	return nil
+}
+
+// Add records sessions that have just been revoked.
+func (l *RevocationList) Add(sessionIds ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range sessionIds {
+		l.revoked[id] = struct{}{}
+		l.added[id] = now
+	}
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"interview/summarization/config"
+	"io"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps uploaded media under slash separated keys.
+type Storage interface {
+	Save(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the driver picked by STORAGE_DRIVER, the local filesystem
+// is used when it is not set.
+func New(cfg config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		dir := cfg.StorageLocalDir
+		if dir == "" {
+			dir = "data"
+		}
+
+		return NewLocal(dir), nil
+	}
+
+	return nil, fmt.Errorf("unsupported storage driver %q", cfg.StorageDriver)
+}
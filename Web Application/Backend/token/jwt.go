@@ -1,21 +1,58 @@
 package token
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredURL       = errors.New("url has expired")
+)
+
+// TokenType keeps a token from being used for something it wasn't issued
+// for, such as a refresh token sent as a bearer token.
+type TokenType string
+
+const (
+	AccessToken       = TokenType("access")
+	RefreshToken      = TokenType("refresh")
+	VerificationToken = TokenType("verification")
+	ChallengeToken    = TokenType("challenge")
+	EnrollmentToken   = TokenType("enrollment")
+)
+
 type JWT interface {
+	URLSigner
 	CreateAccessToken(JWTClaim) (*JWTToken, error)
 	CreateRefreshToken(JWTClaim) (*JWTToken, error)
+	CreateVerificationToken(JWTClaim) (*JWTToken, error)
+	// CreateChallengeToken proves the password of a user with two-factor
+	// authentication, it is traded for the access and refresh tokens
+	// along with their second factor.
+	CreateChallengeToken(JWTClaim) (*JWTToken, error)
+	// CreateEnrollmentToken is emailed to a user who has to set up
+	// two-factor authentication, so the password alone can't enroll an
+	// authenticator.
+	CreateEnrollmentToken(JWTClaim) (*JWTToken, error)
 	GetClaims(token string) (*JWTClaim, error)
 }
 
+// URLSigner hands out short-lived links to a path for clients that cannot
+// send a bearer token, such as the speech to text service.
+type URLSigner interface {
+	SignURL(path string, expiresIn time.Duration) string
+	VerifyURL(path, expires, signature string) error
+}
+
 type JWTClaim struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id,omitempty"`
-	Role   string `json:"role,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Type      TokenType `json:"type,omitempty"`
+	SessionID string    `json:"sid,omitempty"`
 }
 
 type JWTToken struct {
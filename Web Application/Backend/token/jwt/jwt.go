@@ -7,8 +7,13 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const defaultVerificationTokenExpire = 24 // hours
+const defaultChallengeTokenExpire = 5     // minutes
+const defaultEnrollmentTokenExpire = 60   // minutes
+
 type jwtImpl struct {
 	cfg config.Config
 }
@@ -37,10 +42,12 @@ func (j *jwtImpl) CreateAccessToken(claim token.JWTClaim) (*token.JWTToken, erro
 	expAt := now.Add(time.Duration(j.cfg.AccessTokenExpire) * time.Minute)
 
 	registeredClaims := jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
 		ExpiresAt: jwt.NewNumericDate(expAt),
 		IssuedAt:  jwt.NewNumericDate(now),
 	}
 	claim.RegisteredClaims = registeredClaims
+	claim.Type = token.AccessToken
 
 	signedToken, err := j.signToken(&claim)
 	if err != nil {
@@ -61,11 +68,14 @@ func (j *jwtImpl) CreateRefreshToken(claim token.JWTClaim) (*token.JWTToken, err
 	now := time.Now()
 	expAt := now.Add(time.Duration(j.cfg.RefreshTokenExpire) * time.Hour)
 
+	// the id is what the server keeps track of to rotate the token
 	registeredClaims := jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
 		ExpiresAt: jwt.NewNumericDate(expAt),
 		IssuedAt:  jwt.NewNumericDate(now),
 	}
 	claim.RegisteredClaims = registeredClaims
+	claim.Type = token.RefreshToken
 
 	signedToken, err := j.signToken(&claim)
 	if err != nil {
@@ -82,6 +92,99 @@ func (j *jwtImpl) CreateRefreshToken(claim token.JWTClaim) (*token.JWTToken, err
 	return jwtToken, nil
 }
 
+func (j *jwtImpl) CreateVerificationToken(claim token.JWTClaim) (*token.JWTToken, error) {
+	expire := j.cfg.VerificationTokenExpire
+	if expire <= 0 {
+		expire = defaultVerificationTokenExpire
+	}
+
+	now := time.Now()
+	expAt := now.Add(time.Duration(expire) * time.Hour)
+
+	registeredClaims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	claim.RegisteredClaims = registeredClaims
+	claim.Type = token.VerificationToken
+
+	signedToken, err := j.signToken(&claim)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating verification token: %w", err)
+	}
+
+	jwtToken := &token.JWTToken{
+		Token:     signedToken,
+		Claim:     claim,
+		ExpiresAt: expAt,
+	}
+
+	return jwtToken, nil
+}
+
+func (j *jwtImpl) CreateChallengeToken(claim token.JWTClaim) (*token.JWTToken, error) {
+	expire := j.cfg.TwoFactorChallengeExpire
+	if expire <= 0 {
+		expire = defaultChallengeTokenExpire
+	}
+
+	now := time.Now()
+	expAt := now.Add(time.Duration(expire) * time.Minute)
+
+	registeredClaims := jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(expAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	claim.RegisteredClaims = registeredClaims
+	claim.Type = token.ChallengeToken
+
+	signedToken, err := j.signToken(&claim)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating challenge token: %w", err)
+	}
+
+	jwtToken := &token.JWTToken{
+		Token:     signedToken,
+		Claim:     claim,
+		ExpiresAt: expAt,
+		Scheme:    "Bearer",
+	}
+
+	return jwtToken, nil
+}
+
+func (j *jwtImpl) CreateEnrollmentToken(claim token.JWTClaim) (*token.JWTToken, error) {
+	expire := j.cfg.TwoFactorEnrollmentExpire
+	if expire <= 0 {
+		expire = defaultEnrollmentTokenExpire
+	}
+
+	now := time.Now()
+	expAt := now.Add(time.Duration(expire) * time.Minute)
+
+	registeredClaims := jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(expAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	claim.RegisteredClaims = registeredClaims
+	claim.Type = token.EnrollmentToken
+
+	signedToken, err := j.signToken(&claim)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating enrollment token: %w", err)
+	}
+
+	jwtToken := &token.JWTToken{
+		Token:     signedToken,
+		Claim:     claim,
+		ExpiresAt: expAt,
+	}
+
+	return jwtToken, nil
+}
+
 func (j *jwtImpl) GetClaims(tokenString string) (*token.JWTClaim, error) {
 	claim := &token.JWTClaim{}
 	_, err := jwt.ParseWithClaims(tokenString, claim, func(token *jwt.Token) (interface{}, error) {
@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"interview/summarization/token"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func (j *jwtImpl) urlSignature(path, expires string) []byte {
+	mac := hmac.New(sha256.New, []byte(j.cfg.TokenSecret))
+	mac.Write([]byte("url:" + path + ":" + expires))
+	return mac.Sum(nil)
+}
+
+// SignURL returns the path with its expiry and signature in the query.
+func (j *jwtImpl) SignURL(path string, expiresIn time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(expiresIn).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", base64.RawURLEncoding.EncodeToString(j.urlSignature(path, expires)))
+
+	return fmt.Sprintf("%s?%s", path, query.Encode())
+}
+
+func (j *jwtImpl) VerifyURL(path, expires, signature string) error {
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return token.ErrInvalidSignature
+	}
+
+	if !hmac.Equal(sig, j.urlSignature(path, expires)) {
+		return token.ErrInvalidSignature
+	}
+
+	expAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return token.ErrInvalidSignature
+	}
+	if time.Now().Unix() > expAt {
+		return token.ErrExpiredURL
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ const Login = lazy(() => import('./pages/auth/Login'));
 const Register = lazy(() => import('./pages/auth/Register'));
 const Profile = lazy(() => import('./pages/auth/Profile'));
 const VerifyEmail = lazy(() => import('./pages/auth/EmailVerification'));
+const TwoFactorSetup = lazy(() => import('./pages/auth/TwoFactorSetup'));
 const Dashboard = lazy(() => import('./pages/room/Index'));
 const RoomGroupDetail = lazy(() => import('./pages/room/RoomGroupDetail'));
 const Question = lazy(() => import('./pages/question/Index'));
@@ -27,6 +28,7 @@ const App = () => (
               <Route path="/register" element={<Register />} />
               <Route path="/profile" element={<Profile />} />
               <Route path="/auth/verify-email" element={<VerifyEmail />} />
+              <Route path="/auth/two-factor/setup" element={<TwoFactorSetup />} />
               <Route path="/" element={<Dashboard />} />
               <Route path="/room-group/:id" element={<RoomGroupDetail />} />
               <Route path="/room/create" element={<RoomCreate />} />
@@ -1,18 +1,85 @@
 import { AxiosInstance, isAxiosError } from "axios";
-import { AuthData, ProfileData, UserEmail } from "../interface/auth";
+import { AuthData, LoginChallenge, ProfileData, TwoFactorSetup, UserEmail } from "../interface/auth";
 import { ApiError } from "../interface/api";
 
 export async function login(
   axios: AxiosInstance,
   email: string, 
   password: string
-): Promise<AuthData> {
+): Promise<AuthData | LoginChallenge> {
   try {
     const res = await axios.post("/auth/login", {
       email,
       password,
     });
 
+    return res.data as AuthData | LoginChallenge;
+  } catch (e) {
+    if (isAxiosError(e)) {
+      throw new ApiError(e.response?.data.message ? 
+        e.response?.data.message : "Something Went Wrong");
+    }
+
+    throw new ApiError("Something Went Wrong");
+  }
+}
+
+export async function loginTwoFactor(
+  axios: AxiosInstance,
+  challengeToken: string,
+  code: string
+): Promise<AuthData> {
+  // codes of the authenticator are six digits, anything else is a recovery code
+  const isRecoveryCode = !/^\d{6}$/.test(code);
+  try {
+    const res = await axios.post("/auth/login/2fa", {
+      challenge_token: challengeToken,
+      code: isRecoveryCode ? "" : code,
+      recovery_code: isRecoveryCode ? code : "",
+    });
+
+    return res.data as AuthData;
+  } catch (e) {
+    if (isAxiosError(e)) {
+      throw new ApiError(e.response?.data.message ? 
+        e.response?.data.message : "Something Went Wrong");
+    }
+
+    throw new ApiError("Something Went Wrong");
+  }
+}
+
+export async function loginTwoFactorSetup(
+  axios: AxiosInstance,
+  enrollmentToken: string
+): Promise<TwoFactorSetup> {
+  try {
+    const res = await axios.post("/auth/login/2fa/setup", {
+      enrollment_token: enrollmentToken,
+    });
+
+    return res.data as TwoFactorSetup;
+  } catch (e) {
+    if (isAxiosError(e)) {
+      throw new ApiError(e.response?.data.message ? 
+        e.response?.data.message : "Something Went Wrong");
+    }
+
+    throw new ApiError("Something Went Wrong");
+  }
+}
+
+export async function loginTwoFactorEnable(
+  axios: AxiosInstance,
+  enrollmentToken: string,
+  code: string
+): Promise<AuthData> {
+  try {
+    const res = await axios.post("/auth/login/2fa", {
+      enrollment_token: enrollmentToken,
+      code,
+    });
+
     return res.data as AuthData;
   } catch (e) {
     if (isAxiosError(e)) {
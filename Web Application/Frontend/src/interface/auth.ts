@@ -8,6 +8,18 @@ export interface AuthData {
   access_token: token,
   refresh_token: token,
   role: string,
+  recovery_codes?: string[],
+}
+
+export interface LoginChallenge {
+  two_factor_required: boolean,
+  setup_required: boolean,
+  challenge_token: token,
+}
+
+export interface TwoFactorSetup {
+  secret: string,
+  provisioning_uri: string,
 }
 
 export interface ProfileData {
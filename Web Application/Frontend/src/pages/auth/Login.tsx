@@ -1,9 +1,10 @@
 import { useContext, useEffect, useState } from "react";
 import { useNavigate } from "react-router-dom";
 import { AuthContext } from "../../utils/context/auth";
-import { login } from "../../api/auth";
+import { login, loginTwoFactor } from "../../api/auth";
 import { ApiContext } from "../../utils/context/api";
 import { ApiError } from "../../interface/api";
+import { AuthData, LoginChallenge } from "../../interface/auth";
 import ToastModal from "../../components/ToastModal";
 import { Box,
   Container,
@@ -37,7 +38,9 @@ const Login = () => {
   const [isErrorEmail, setIsErrorEmail] = useState<boolean>(false);
   const [isErrorPassword, setIsErrorPassword] = useState<boolean>(false);
   const [isSubmit, setIsSubmit] = useState<boolean>(false);
-
+  const [challengeToken, setChallengeToken] = useState<string>("");
+  const [code, setCode] = useState<string>("");
+  const [isErrorCode, setIsErrorCode] = useState<boolean>(false);
 
   const handleSubmit = async () => {
     try {
@@ -47,8 +50,18 @@ const Login = () => {
       if (email === "" || password === "") {
         return;
       }
-      const authData = await login(apiContext.axios, email, password);
-      authContext.login(authData);
+      const res = await login(apiContext.axios, email, password);
+      const challenge = res as LoginChallenge;
+      if (challenge.two_factor_required) {
+        if (challenge.setup_required) {
+          ToastModal(toast, "Info", "Akun ini wajib menggunakan autentikasi dua faktor. Silakan cek email Anda untuk mengaturnya terlebih dahulu.", "info");
+          return;
+        }
+
+        setChallengeToken(challenge.challenge_token.token);
+        return;
+      }
+      authContext.login(res as AuthData);
 
       console.log(navigate(-1));
       navigate("/");
@@ -60,12 +73,48 @@ const Login = () => {
       }
     }
   }
+
+  const handleSubmitCode = async () => {
+    try {
+      setIsErrorCode(code === "");
+      if (code === "") {
+        return;
+      }
+      const authData = await loginTwoFactor(apiContext.axios, challengeToken, code);
+      authContext.login(authData);
+
+      navigate("/");
+    } catch (e) {
+      if (e instanceof ApiError) {
+        ToastModal(toast, "Error!", e.message, "error");
+      } else {
+        ToastModal(toast, "Error!", "Terjadi kesalahan pada server.", "error");
+      }
+    }
+  }
   
   return (
     <Box h="fit-content" minH="100vh" w="100vw" display="flex" flexDir="column" alignItems="flex-start" bg="main_bg">
       <Image src="../assets/hiremif_logo.png" alt="HireMIF" h="50px" mx="10" mt="6"/>
       <Container boxShadow="2xl" p="6" rounded="md" bg="white" mx="auto" my="auto">
         <Text as="h1" fontSize="3xl" justifyItems="left" fontWeight="extrabold" textColor="main_blue">Masuk HireMIF</Text>
+        {challengeToken !== "" ? (
+        <Container as="form" mt="2rem" onSubmit={e => {
+          e.preventDefault();
+          handleSubmitCode();
+        }}>
+          <FormControl isInvalid={isErrorCode} mb="4">
+            <FormLabel>Kode Autentikasi</FormLabel>
+            <Input value={code} onChange={e => setCode(e.target.value)} placeholder="123456" autoComplete="one-time-code" mt="-2"/>
+            {!isErrorCode ? (
+              <Text fontSize="sm" mt="1">Masukkan kode dari aplikasi autentikator atau salah satu kode pemulihan.</Text>
+            ) : (
+              <FormErrorMessage>Code is required.</FormErrorMessage>
+            )}
+          </FormControl>
+          <Button bg="main_blue" color="white" w="100%" type="submit" mt="2rem" _hover={{ bg: "second_blue" }}>Verifikasi</Button>
+        </Container>
+        ) : (
         <Container as="form" mt="2rem" onSubmit={e => {
           e.preventDefault();
           handleSubmit();
@@ -97,6 +146,7 @@ const Login = () => {
           </FormControl>
           <Button bg="main_blue" color="white" w="100%" type="submit" mt="2rem" _hover={{ bg: "second_blue" }}>Login</Button>
         </Container>
+        )}
       </Container>
       <Container display="flex" justifyContent="center" my="auto">
         <Text>
@@ -0,0 +1,151 @@
+import { useContext, useEffect, useState } from "react";
+import { useLocation, useNavigate } from "react-router-dom";
+import { AuthContext } from "../../utils/context/auth";
+import { loginTwoFactorEnable, loginTwoFactorSetup } from "../../api/auth";
+import { ApiContext } from "../../utils/context/api";
+import { ApiError } from "../../interface/api";
+import { AuthData, TwoFactorSetup as TwoFactorSetupData } from "../../interface/auth";
+import ToastModal from "../../components/ToastModal";
+import { Box,
+  Container,
+  FormControl,
+  FormLabel,
+  FormErrorMessage,
+  Text,
+  Input,
+  Button,
+  Code,
+  useToast,
+  Image,
+  Spinner } from "@chakra-ui/react"
+
+const TwoFactorSetup = () => {
+  const location = useLocation();
+  const navigate = useNavigate();
+  const authContext = useContext(AuthContext);
+  const apiContext = useContext(ApiContext);
+  const toast = useToast();
+
+  const [enrollmentToken, setEnrollmentToken] = useState<string>("");
+  const [setup, setSetup] = useState<TwoFactorSetupData | null>(null);
+  const [status, setStatus] = useState<string>("");
+  const [code, setCode] = useState<string>("");
+  const [isErrorCode, setIsErrorCode] = useState<boolean>(false);
+  const [authData, setAuthData] = useState<AuthData | null>(null);
+
+  useEffect(() => {
+    const setupAsync = async () => {
+      const queryParams = new URLSearchParams(location.search);
+      const token = queryParams.get('token');
+
+      try {
+        if (token) {
+          setEnrollmentToken(token);
+          setSetup(await loginTwoFactorSetup(apiContext.axios, token));
+        } else {
+          setStatus('Invalid setup link.');
+        }
+      } catch (e) {
+        if (e instanceof ApiError) {
+          setStatus(e.message);
+        } else {
+          setStatus('An error occurred while setting up two-factor authentication.');
+        }
+      }
+    };
+
+    setupAsync();
+  }, [location.search, apiContext]);
+
+  const handleSubmit = async () => {
+    try {
+      setIsErrorCode(code === "");
+      if (code === "") {
+        return;
+      }
+      setAuthData(await loginTwoFactorEnable(apiContext.axios, enrollmentToken, code));
+    } catch (e) {
+      if (e instanceof ApiError) {
+        ToastModal(toast, "Error!", e.message, "error");
+      } else {
+        ToastModal(toast, "Error!", "Terjadi kesalahan pada server.", "error");
+      }
+    }
+  }
+
+  const handleContinue = () => {
+    if (authData === null) {
+      return;
+    }
+    authContext.login(authData);
+
+    navigate("/");
+  }
+
+  if (setup === null) {
+    return (
+      <Box h="fit-content" minH="100vh" w="100vw" display="flex" flexDir="column" alignItems="center" bg="main_bg">
+        <Image src="../../assets/hiremif_logo.png" alt="HireMIF" w="400px" mx="10" mt="20" mb="20"/>
+        {status === "" ? (
+          <Container display="flex" flexDir="column" alignItems="center" mb="10">
+            <Spinner
+              thickness='4px'
+              speed='0.65s'
+              emptyColor='gray.200'
+              color='blue.500'
+              size='xl'
+            />
+          </Container>
+        ) : (
+          <Text
+            fontSize="xl"
+            fontWeight="bold"
+            color="main_text"
+            mb="10"
+          >{status}</Text>
+        )}
+      </Box>
+    )
+  }
+
+  return (
+    <Box h="fit-content" minH="100vh" w="100vw" display="flex" flexDir="column" alignItems="flex-start" bg="main_bg">
+      <Image src="../../assets/hiremif_logo.png" alt="HireMIF" h="50px" mx="10" mt="6"/>
+      <Container boxShadow="2xl" p="6" rounded="md" bg="white" mx="auto" my="auto">
+        <Text as="h1" fontSize="3xl" justifyItems="left" fontWeight="extrabold" textColor="main_blue">Atur Autentikasi Dua Faktor</Text>
+        {authData !== null ? (
+        <Container mt="2rem">
+          <Text mb="2">Simpan kode pemulihan berikut di tempat yang aman. Setiap kode hanya dapat digunakan satu kali jika Anda kehilangan akses ke aplikasi autentikator.</Text>
+          {authData.recovery_codes?.map(recoveryCode => (
+            <Code key={recoveryCode} display="block" mb="1">{recoveryCode}</Code>
+          ))}
+          <Button bg="main_blue" color="white" w="100%" mt="2rem" _hover={{ bg: "second_blue" }} onClick={handleContinue}>Lanjutkan</Button>
+        </Container>
+        ) : (
+        <Container as="form" mt="2rem" onSubmit={e => {
+          e.preventDefault();
+          handleSubmit();
+        }}>
+          <Text mb="2">Tambahkan akun berikut ke aplikasi autentikator Anda, lalu masukkan kode yang ditampilkan.</Text>
+          <Text fontWeight="bold">Secret</Text>
+          <Code display="block" mb="2">{setup.secret}</Code>
+          <Text fontWeight="bold">URI</Text>
+          <Code display="block" mb="4" wordBreak="break-all">{setup.provisioning_uri}</Code>
+          <FormControl isInvalid={isErrorCode} mb="4">
+            <FormLabel>Kode Autentikasi</FormLabel>
+            <Input value={code} onChange={e => setCode(e.target.value)} placeholder="123456" autoComplete="one-time-code" mt="-2"/>
+            {!isErrorCode ? (
+              <></>
+            ) : (
+              <FormErrorMessage>Code is required.</FormErrorMessage>
+            )}
+          </FormControl>
+          <Button bg="main_blue" color="white" w="100%" type="submit" mt="2rem" _hover={{ bg: "second_blue" }}>Aktifkan</Button>
+        </Container>
+        )}
+      </Container>
+    </Box>
+  )
+}
+
+export default TwoFactorSetup;